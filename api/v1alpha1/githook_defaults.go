@@ -0,0 +1,31 @@
+package v1alpha1
+
+// DefaultServiceAccountName is the service account of GitHook without serviceAccountName
+const DefaultServiceAccountName = "pipeline-runner"
+
+// DefaultEventTypes are the event types of GitHook without eventTypes
+var DefaultEventTypes = []GitEvent{EventPush, EventPullRequest}
+
+// GetServiceAccountName returns the service account of GitHook or the default one
+func (spec *GitHookSpec) GetServiceAccountName() string {
+	if spec.ServiceAccountName == "" {
+		return DefaultServiceAccountName
+	}
+
+	return spec.ServiceAccountName
+}
+
+// GetEventTypes returns the event types of GitHook or the default ones
+func (spec *GitHookSpec) GetEventTypes() []GitEvent {
+	if len(spec.EventTypes) == 0 {
+		return DefaultEventTypes
+	}
+
+	return spec.EventTypes
+}
+
+// SetDefaults sets default values of unspecified fields of GitHook
+func (spec *GitHookSpec) SetDefaults() {
+	spec.ServiceAccountName = spec.GetServiceAccountName()
+	spec.EventTypes = append([]GitEvent{}, spec.GetEventTypes()...)
+}
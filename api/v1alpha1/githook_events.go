@@ -0,0 +1,141 @@
+package v1alpha1
+
+import (
+	"fmt"
+)
+
+// GitEvents are all event types of GitHook in the order of the enum of GitEvent
+var GitEvents = []GitEvent{
+	EventCreate, EventDelete, EventFork, EventPush, EventTagPush, EventIssues, EventIssueComment,
+	EventPullRequest, EventReview, EventReviewComment, EventRelease,
+	EventPipeline, EventWiki, EventDeployment, EventStatus, EventMember,
+}
+
+// providerEvents maps event types each git provider supports to the events of the provider
+// webhook is registered for. Providers sending tag pushes as push events register push for tag_push.
+var providerEvents = map[GitProvider]map[GitEvent][]string{
+	Github: {
+		EventCreate:        {"create"},
+		EventDelete:        {"delete"},
+		EventFork:          {"fork"},
+		EventPush:          {"push"},
+		EventTagPush:       {"push"},
+		EventIssues:        {"issues"},
+		EventIssueComment:  {"issue_comment"},
+		EventPullRequest:   {"pull_request"},
+		EventReview:        {"pull_request_review"},
+		EventReviewComment: {"pull_request_review_comment"},
+		EventRelease:       {"release"},
+		EventWiki:          {"gollum"},
+		EventDeployment:    {"deployment"},
+		EventStatus:        {"status"},
+		EventMember:        {"member"},
+	},
+	Gogs: {
+		EventCreate:       {"create"},
+		EventDelete:       {"delete"},
+		EventFork:         {"fork"},
+		EventPush:         {"push"},
+		EventTagPush:      {"push"},
+		EventIssues:       {"issues"},
+		EventIssueComment: {"issue_comment"},
+		EventPullRequest:  {"pull_request"},
+		EventRelease:      {"release"},
+	},
+	Gitea: {
+		EventCreate:       {"create"},
+		EventDelete:       {"delete"},
+		EventFork:         {"fork"},
+		EventPush:         {"push"},
+		EventTagPush:      {"push"},
+		EventIssues:       {"issues"},
+		EventIssueComment: {"issue_comment"},
+		EventPullRequest:  {"pull_request"},
+		EventReview:       {"pull_request_review"},
+		EventRelease:      {"release"},
+	},
+	// gitlab hooks are registered with boolean attributes of the hook
+	Gitlab: {
+		EventPush:         {"push_events"},
+		EventTagPush:      {"tag_push_events"},
+		EventIssues:       {"issues_events"},
+		EventIssueComment: {"note_events"},
+		EventPullRequest:  {"merge_requests_events"},
+		EventPipeline:     {"pipeline_events"},
+		EventWiki:         {"wiki_page_events"},
+	},
+	Bitbucket: {
+		EventPush:        {"repo:push"},
+		EventTagPush:     {"repo:push"},
+		EventPullRequest: {"pullrequest:created", "pullrequest:updated"},
+	},
+	BitbucketServer: {
+		EventPush:        {"repo:refs_changed"},
+		EventTagPush:     {"repo:refs_changed"},
+		EventPullRequest: {"pr:opened", "pr:from_ref_updated"},
+	},
+	AzureDevOps: {
+		EventPush:        {"git.push"},
+		EventTagPush:     {"git.push"},
+		EventPullRequest: {"git.pullrequest.created", "git.pullrequest.updated"},
+	},
+}
+
+// UnsupportedEventsError is returned when event types are not supported by git provider
+// +kubebuilder:object:generate=false
+type UnsupportedEventsError struct {
+	Provider   GitProvider
+	EventTypes []GitEvent
+}
+
+func (err UnsupportedEventsError) Error() string {
+	return fmt.Sprintf("event types %v are not supported by provider %s, supported event types are %v",
+		err.EventTypes, err.Provider, err.Provider.SupportedEvents())
+}
+
+// SupportedEvents returns the event types git provider can register webhook for
+func (provider GitProvider) SupportedEvents() []GitEvent {
+	supported := []GitEvent{}
+
+	for _, event := range GitEvents {
+		if provider.SupportsEvent(event) {
+			supported = append(supported, event)
+		}
+	}
+
+	return supported
+}
+
+// SupportsEvent checks if git provider can register webhook for event type
+func (provider GitProvider) SupportsEvent(event GitEvent) bool {
+	_, ok := providerEvents[provider][event]
+	return ok
+}
+
+// ProviderEvents returns the events of git provider webhook is registered for to receive
+// event types. It returns UnsupportedEventsError if any event type is not supported.
+func (provider GitProvider) ProviderEvents(eventTypes []GitEvent) ([]string, error) {
+	events := []string{}
+	added := make(map[string]bool)
+	unsupported := []GitEvent{}
+
+	for _, eventType := range eventTypes {
+		if !provider.SupportsEvent(eventType) {
+			unsupported = append(unsupported, eventType)
+			continue
+		}
+
+		for _, event := range providerEvents[provider][eventType] {
+			if !added[event] {
+				added[event] = true
+				events = append(events, event)
+			}
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return nil, UnsupportedEventsError{Provider: provider, EventTypes: unsupported}
+	}
+
+	return events, nil
+}
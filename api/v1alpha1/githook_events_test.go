@@ -0,0 +1,64 @@
+package v1alpha1
+
+import (
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGitEventsMatchEnum(t *testing.T) {
+	source, err := ioutil.ReadFile("githook_types.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	match := regexp.MustCompile(`// \+kubebuilder:validation:Enum=(.*)\n\n// GitEvent `).FindSubmatch(source)
+	if match == nil {
+		t.Fatal("enum marker of GitEvent not found")
+	}
+
+	enum := []GitEvent{}
+	for _, event := range strings.Split(string(match[1]), ";") {
+		enum = append(enum, GitEvent(event))
+	}
+
+	if !reflect.DeepEqual(enum, GitEvents) {
+		t.Fatalf("expected enum of GitEvent %v to match GitEvents %v", enum, GitEvents)
+	}
+}
+
+func TestProviderEvents(t *testing.T) {
+	tests := []struct {
+		provider   GitProvider
+		eventTypes []GitEvent
+		expected   []string
+		err        string
+	}{
+		{provider: Github, eventTypes: []GitEvent{EventPush, EventTagPush, EventReview, EventWiki}, expected: []string{"push", "pull_request_review", "gollum"}},
+		{provider: Gitlab, eventTypes: []GitEvent{EventPush, EventTagPush, EventPipeline}, expected: []string{"push_events", "tag_push_events", "pipeline_events"}},
+		{provider: Bitbucket, eventTypes: []GitEvent{EventPullRequest}, expected: []string{"pullrequest:created", "pullrequest:updated"}},
+		{provider: Gitlab, eventTypes: []GitEvent{EventPush, EventCreate, EventRelease}, err: "event types [create release] are not supported by provider gitlab"},
+		{provider: AzureDevOps, eventTypes: []GitEvent{EventPipeline}, err: "supported event types are [push tag_push pull_request]"},
+	}
+
+	for _, test := range tests {
+		events, err := test.provider.ProviderEvents(test.eventTypes)
+
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error %q of %s events %v but got %v", test.err, test.provider, test.eventTypes, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !reflect.DeepEqual(events, test.expected) {
+			t.Fatalf("expected %s events %v but got %v", test.provider, test.expected, events)
+		}
+	}
+}
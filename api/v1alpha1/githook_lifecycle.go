@@ -0,0 +1,208 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionType is the type of GitHook condition
+type ConditionType string
+
+const (
+	// GitHookConditionReady is true when all other conditions are true
+	GitHookConditionReady ConditionType = "Ready"
+
+	// GitHookConditionSecretsResolved is true when access token and secret token are read from secrets
+	GitHookConditionSecretsResolved ConditionType = "SecretsResolved"
+
+	// GitHookConditionReceiverReady is true when knative service receiving webhook is ready
+	GitHookConditionReceiverReady ConditionType = "ReceiverReady"
+
+	// GitHookConditionWebhookRegistered is true when webhook is registered on the git provider
+	GitHookConditionWebhookRegistered ConditionType = "WebhookRegistered"
+)
+
+// gitHookDependentConditions are the conditions Ready depends on
+var gitHookDependentConditions = []ConditionType{
+	GitHookConditionSecretsResolved,
+	GitHookConditionReceiverReady,
+	GitHookConditionWebhookRegistered,
+}
+
+// Condition defines a readiness condition of GitHook (knative style)
+type Condition struct {
+	// Type of condition
+	Type ConditionType `json:"type"`
+
+	// Status of the condition, one of True, False, Unknown
+	Status corev1.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the last time the condition transitioned from one status to another
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a one-word CamelCase reason for the condition's last transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable message indicating details about last transition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// GetCondition returns condition of the given type or nil if not found
+func (s *GitHookStatus) GetCondition(t ConditionType) *Condition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == t {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsReady checks if the GitHook is ready
+func (s *GitHookStatus) IsReady() bool {
+	condition := s.GetCondition(GitHookConditionReady)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// InitializeConditions sets all unset conditions to unknown
+func (s *GitHookStatus) InitializeConditions() {
+	for _, t := range append(gitHookDependentConditions, GitHookConditionReady) {
+		if s.GetCondition(t) == nil {
+			s.setCondition(Condition{Type: t, Status: corev1.ConditionUnknown})
+		}
+	}
+}
+
+// MarkSecretsResolved sets SecretsResolved condition to true
+func (s *GitHookStatus) MarkSecretsResolved() {
+	s.markTrue(GitHookConditionSecretsResolved)
+}
+
+// MarkSecretsNotResolved sets SecretsResolved condition to false
+func (s *GitHookStatus) MarkSecretsNotResolved(reason, messageFormat string, messageA ...interface{}) {
+	s.markFalse(GitHookConditionSecretsResolved, reason, messageFormat, messageA...)
+}
+
+// MarkReceiverReady sets ReceiverReady condition to true with the webhook url
+func (s *GitHookStatus) MarkReceiverReady(webhookURL string) {
+	s.WebhookURL = webhookURL
+	s.markTrue(GitHookConditionReceiverReady)
+}
+
+// MarkReceiverNotReady sets ReceiverReady condition to false
+func (s *GitHookStatus) MarkReceiverNotReady(reason, messageFormat string, messageA ...interface{}) {
+	s.markFalse(GitHookConditionReceiverReady, reason, messageFormat, messageA...)
+}
+
+// MarkReceiverDeploying sets ReceiverReady condition to unknown while the service is being deployed
+func (s *GitHookStatus) MarkReceiverDeploying(reason, messageFormat string, messageA ...interface{}) {
+	s.markUnknown(GitHookConditionReceiverReady, reason, messageFormat, messageA...)
+}
+
+// MarkWebhookRegistered sets WebhookRegistered condition to true with the provider hook id
+func (s *GitHookStatus) MarkWebhookRegistered(hookID string) {
+	s.ID = hookID
+	s.markTrue(GitHookConditionWebhookRegistered)
+}
+
+// MarkWebhookNotRegistered sets WebhookRegistered condition to false
+func (s *GitHookStatus) MarkWebhookNotRegistered(reason, messageFormat string, messageA ...interface{}) {
+	s.markFalse(GitHookConditionWebhookRegistered, reason, messageFormat, messageA...)
+}
+
+func (s *GitHookStatus) markTrue(t ConditionType) {
+	s.setCondition(Condition{Type: t, Status: corev1.ConditionTrue})
+	s.updateReady()
+}
+
+func (s *GitHookStatus) markFalse(t ConditionType, reason, messageFormat string, messageA ...interface{}) {
+	s.setCondition(Condition{
+		Type:    t,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: fmt.Sprintf(messageFormat, messageA...),
+	})
+	s.updateReady()
+}
+
+func (s *GitHookStatus) markUnknown(t ConditionType, reason, messageFormat string, messageA ...interface{}) {
+	s.setCondition(Condition{
+		Type:    t,
+		Status:  corev1.ConditionUnknown,
+		Reason:  reason,
+		Message: fmt.Sprintf(messageFormat, messageA...),
+	})
+	s.updateReady()
+}
+
+// updateReady derives Ready condition from dependent conditions
+func (s *GitHookStatus) updateReady() {
+	ready := Condition{Type: GitHookConditionReady, Status: corev1.ConditionTrue}
+
+	for _, t := range gitHookDependentConditions {
+		condition := s.GetCondition(t)
+
+		if condition == nil || condition.Status == corev1.ConditionUnknown {
+			if ready.Status == corev1.ConditionTrue {
+				ready = Condition{Type: GitHookConditionReady, Status: corev1.ConditionUnknown}
+				if condition != nil {
+					ready.Reason = condition.Reason
+					ready.Message = condition.Message
+				}
+			}
+			continue
+		}
+
+		if condition.Status == corev1.ConditionFalse {
+			ready = Condition{
+				Type:    GitHookConditionReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  condition.Reason,
+				Message: condition.Message,
+			}
+			break
+		}
+	}
+
+	s.setCondition(ready)
+}
+
+// setCondition sets condition keeping last transition time if status is not changed
+func (s *GitHookStatus) setCondition(new Condition) {
+	existing := s.GetCondition(new.Type)
+
+	if existing != nil {
+		if existing.Status == new.Status && existing.Reason == new.Reason && existing.Message == new.Message {
+			return
+		}
+
+		new.LastTransitionTime = existing.LastTransitionTime
+		if existing.Status != new.Status {
+			new.LastTransitionTime = metav1.Now()
+		}
+		*existing = new
+		return
+	}
+
+	new.LastTransitionTime = metav1.Now()
+	s.Conditions = append(s.Conditions, new)
+}
@@ -0,0 +1,84 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGitHookStatusLifecycle(t *testing.T) {
+	status := &GitHookStatus{}
+	status.InitializeConditions()
+
+	for _, conditionType := range []ConditionType{
+		GitHookConditionReady,
+		GitHookConditionSecretsResolved,
+		GitHookConditionReceiverReady,
+		GitHookConditionWebhookRegistered,
+	} {
+		condition := status.GetCondition(conditionType)
+		if condition == nil || condition.Status != corev1.ConditionUnknown {
+			t.Fatalf("expected condition %s to be initialized as unknown but got %v", conditionType, condition)
+		}
+	}
+
+	status.MarkSecretsResolved()
+	status.MarkReceiverReady("http://hook.default.example.com")
+
+	if status.IsReady() {
+		t.Fatalf("expected not ready before webhook is registered")
+	}
+
+	status.MarkWebhookRegistered("12")
+
+	if !status.IsReady() {
+		t.Fatalf("expected ready but got %v", status.GetCondition(GitHookConditionReady))
+	}
+
+	if status.ID != "12" || status.WebhookURL != "http://hook.default.example.com" {
+		t.Fatalf("expected hook id and webhook url to be set but got %s and %s", status.ID, status.WebhookURL)
+	}
+
+	status.MarkWebhookNotRegistered("ProviderError", "failed to add webhook: %s", "401 Unauthorized")
+
+	ready := status.GetCondition(GitHookConditionReady)
+	if ready.Status != corev1.ConditionFalse || ready.Reason != "ProviderError" || ready.Message != "failed to add webhook: 401 Unauthorized" {
+		t.Fatalf("expected ready to be false with reason of webhook registration but got %v", ready)
+	}
+
+	status.MarkReceiverDeploying("ServiceDeploying", "waiting for service")
+	status.MarkWebhookRegistered("12")
+
+	ready = status.GetCondition(GitHookConditionReady)
+	if ready.Status != corev1.ConditionUnknown || ready.Reason != "ServiceDeploying" {
+		t.Fatalf("expected ready to be unknown while receiver is deploying but got %v", ready)
+	}
+}
+
+func TestGitHookStatusKeepsTransitionTime(t *testing.T) {
+	status := &GitHookStatus{}
+	status.MarkSecretsResolved()
+
+	before := status.GetCondition(GitHookConditionSecretsResolved).LastTransitionTime
+	status.MarkSecretsResolved()
+	after := status.GetCondition(GitHookConditionSecretsResolved).LastTransitionTime
+
+	if !before.Equal(&after) {
+		t.Fatalf("expected last transition time to be kept when status is not changed")
+	}
+}
@@ -19,6 +19,7 @@ import (
 	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -30,7 +31,33 @@ type SecretValueFromSource struct {
 	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=gitlab;github;gogs
+// CABundleSource represents the source of PEM encoded CA certificates.
+// Exactly one of ConfigMapKeyRef or SecretKeyRef should be given.
+type CABundleSource struct {
+	// The ConfigMap key to select from.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// The Secret key to select from.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// GithubAppCredentials represents credentials of a github app installation
+type GithubAppCredentials struct {
+	// AppID is the id of the github app
+	// +kubebuilder:validation:Minimum=1
+	AppID int64 `json:"appId"`
+
+	// InstallationID is the id of the github app installation on the project owner
+	// +kubebuilder:validation:Minimum=1
+	InstallationID int64 `json:"installationId"`
+
+	// PrivateKey is the Kubernetes secret containing PEM encoded private key of the github app
+	PrivateKey SecretValueFromSource `json:"privateKey"`
+}
+
+// +kubebuilder:validation:Enum=gitlab;github;gogs;gitea;bitbucket;bitbucketserver;azuredevops
 
 // GitProvider providers name of git provider
 type GitProvider string
@@ -44,10 +71,303 @@ var (
 
 	// Gogs gogs compatible
 	Gogs GitProvider = "gogs"
+
+	// Gitea gitea compatible
+	Gitea GitProvider = "gitea"
+
+	// Bitbucket bitbucket.org compatible
+	Bitbucket GitProvider = "bitbucket"
+
+	// BitbucketServer bitbucket server and data center compatible
+	BitbucketServer GitProvider = "bitbucketserver"
+
+	// AzureDevOps azure devops repos compatible
+	AzureDevOps GitProvider = "azuredevops"
 )
 
-// +kubebuilder:validation:Enum=create;delete;fork;push;issues;issue_comment;pull_request;release
-type gitEvent string
+// +kubebuilder:validation:Enum=create;delete;fork;push;tag_push;issues;issue_comment;pull_request;review;review_comment;release;pipeline;wiki;deployment;status;member
+
+// GitEvent name of the type of git event. Git providers support different event types,
+// see SupportedEvents of GitProvider.
+type GitEvent string
+
+var (
+	// EventCreate branch or tag created
+	EventCreate GitEvent = "create"
+
+	// EventDelete branch or tag deleted
+	EventDelete GitEvent = "delete"
+
+	// EventFork project forked
+	EventFork GitEvent = "fork"
+
+	// EventPush commits pushed to a branch
+	EventPush GitEvent = "push"
+
+	// EventTagPush tag pushed
+	EventTagPush GitEvent = "tag_push"
+
+	// EventIssues issue opened, edited or closed
+	EventIssues GitEvent = "issues"
+
+	// EventIssueComment comment on issue or pull request
+	EventIssueComment GitEvent = "issue_comment"
+
+	// EventPullRequest pull request opened or updated
+	EventPullRequest GitEvent = "pull_request"
+
+	// EventReview pull request reviewed
+	EventReview GitEvent = "review"
+
+	// EventReviewComment comment on pull request diff
+	EventReviewComment GitEvent = "review_comment"
+
+	// EventRelease release published
+	EventRelease GitEvent = "release"
+
+	// EventPipeline ci pipeline status changed
+	EventPipeline GitEvent = "pipeline"
+
+	// EventWiki wiki page created or updated
+	EventWiki GitEvent = "wiki"
+
+	// EventDeployment deployment created
+	EventDeployment GitEvent = "deployment"
+
+	// EventStatus commit status changed
+	EventStatus GitEvent = "status"
+
+	// EventMember collaborator added or removed
+	EventMember GitEvent = "member"
+)
+
+// +kubebuilder:validation:Enum=vars;template
+
+// RenderMode name of the way runspec is rendered with event data
+type RenderMode string
+
+var (
+	// RenderVars replaces $VARIABLE in runspec with event data
+	RenderVars RenderMode = "vars"
+
+	// RenderTemplate renders string values in runspec as go templates
+	RenderTemplate RenderMode = "template"
+)
+
+// +kubebuilder:validation:Enum=project;organization
+
+// HookScope name of the level webhook is registered on
+type HookScope string
+
+var (
+	// ScopeProject registers webhook on the project
+	ScopeProject HookScope = "project"
+
+	// ScopeOrganization registers webhook on the organization (github, gitea) or group (gitlab)
+	ScopeOrganization HookScope = "organization"
+)
+
+// +kubebuilder:validation:Enum=Allow;CancelPrevious;Queue
+
+// ConcurrencyPolicy name of the way pipelineruns of the same branch or pull request run concurrently
+type ConcurrencyPolicy string
+
+var (
+	// ConcurrencyAllow runs pipelineruns of the same branch or pull request concurrently
+	ConcurrencyAllow ConcurrencyPolicy = "Allow"
+
+	// ConcurrencyCancelPrevious cancels running pipelineruns of the same branch or pull request
+	// once the new one is created
+	ConcurrencyCancelPrevious ConcurrencyPolicy = "CancelPrevious"
+
+	// ConcurrencyQueue holds the new pipelinerun until running pipelineruns of the same
+	// branch or pull request finish
+	ConcurrencyQueue ConcurrencyPolicy = "Queue"
+)
+
+// +kubebuilder:validation:Enum=tekton.dev/v1alpha1;tekton.dev/v1beta1;tekton.dev/v1
+
+// PipelineAPIVersion api version of tekton pipelineruns created by GitHook
+type PipelineAPIVersion string
+
+var (
+	// PipelineV1alpha1 creates tekton.dev/v1alpha1 pipelineruns from RunSpec with git pipelineresource
+	PipelineV1alpha1 PipelineAPIVersion = "tekton.dev/v1alpha1"
+
+	// PipelineV1beta1 creates tekton.dev/v1beta1 pipelineruns from PipelineRunSpec with git params
+	PipelineV1beta1 PipelineAPIVersion = "tekton.dev/v1beta1"
+
+	// PipelineV1 creates tekton.dev/v1 pipelineruns from PipelineRunSpec with git params
+	PipelineV1 PipelineAPIVersion = "tekton.dev/v1"
+)
+
+// WorkspaceTemplate binds a workspace of every triggered pipelinerun to a volume.
+// Exactly one of EmptyDir, VolumeClaimTemplate and PersistentVolumeClaim must be given.
+type WorkspaceTemplate struct {
+	// Name is the name of the workspace declared by the pipeline
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// SubPath is the directory of the volume bound as workspace
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
+	// EmptyDir binds an empty directory living as long as the taskrun pod
+	// +optional
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+
+	// VolumeClaimTemplate lets tekton create a persistentvolumeclaim for every pipelinerun
+	// +optional
+	VolumeClaimTemplate *corev1.PersistentVolumeClaim `json:"volumeClaimTemplate,omitempty"`
+
+	// PersistentVolumeClaim is the spec of persistentvolumeclaim created for every pipelinerun
+	// before it is created. The claim is owned by the pipelinerun so it is deleted with it.
+	// +optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimSpec `json:"persistentVolumeClaim,omitempty"`
+}
+
+// RepositoryRoute selects the pipelinerun spec run for events of matching repositories
+type RepositoryRoute struct {
+	// Repositories are the patterns of which one must match the repository name
+	// (without owner) of the event. Patterns are the same as for filters.
+	// +kubebuilder:validation:MinItems=1
+	Repositories []string `json:"repositories"`
+
+	// RunSpec is a tekton.dev/v1alpha1 pipelinerun spec to be run for events of matching repositories
+	// +optional
+	RunSpec tektonv1alpha1.PipelineRunSpec `json:"runspec,omitempty"`
+
+	// PipelineRunSpec is the pipelinerun spec of PipelineAPIVersion tekton.dev/v1beta1
+	// or tekton.dev/v1 to be run for events of matching repositories
+	// +optional
+	PipelineRunSpec *runtime.RawExtension `json:"pipelineRunSpec,omitempty"`
+}
+
+// CommitStatusReport configures reporting results of pipelineruns on the triggering commit
+type CommitStatusReport struct {
+	// Context is the name identifying the status on the commit. Defaults to githook/<name>.
+	// +optional
+	Context string `json:"context,omitempty"`
+
+	// TargetURL is the url linked from the status to see pipelinerun details (ex. tekton dashboard).
+	// $NAMESPACE and $NAME are replaced with namespace and name of the pipelinerun.
+	// +optional
+	TargetURL string `json:"targetUrl,omitempty"`
+
+	// CheckRun reports github check runs instead of commit statuses.
+	// Check runs can only be reported by github apps.
+	// +optional
+	CheckRun bool `json:"checkRun,omitempty"`
+}
+
+// RetentionPolicy limits the finished pipelineruns kept for a GitHook.
+// Pipelineruns exceeding any of the limits are deleted. Running pipelineruns are never deleted.
+type RetentionPolicy struct {
+	// SuccessfulRunsLimit is the number of the latest succeeded pipelineruns to keep
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuccessfulRunsLimit *int32 `json:"successfulRunsLimit,omitempty"`
+
+	// FailedRunsLimit is the number of the latest failed or cancelled pipelineruns to keep
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailedRunsLimit *int32 `json:"failedRunsLimit,omitempty"`
+
+	// MaxAge is the duration after which finished pipelineruns are deleted (ex. 72h)
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// DeleteGitResources deletes git pipelineresources created for the GitHook
+	// once no pipelinerun refers to them
+	// +optional
+	DeleteGitResources bool `json:"deleteGitResources,omitempty"`
+}
+
+// GitHookFilters restricts which events trigger a pipeline run.
+// A pattern is either a glob, where * matches any character except / and
+// ** matches any character, or a regular expression enclosed in slashes
+// (e.g. /^release-[0-9]+$/).
+type GitHookFilters struct {
+	// Branches are the patterns a branch must match to trigger a pipeline run.
+	// For pull request events the source branch is matched.
+	// +optional
+	Branches []string `json:"branches,omitempty"`
+
+	// BranchesIgnore are the patterns of branches which never trigger a pipeline run
+	// +optional
+	BranchesIgnore []string `json:"branchesIgnore,omitempty"`
+
+	// Tags are the patterns a tag must match to trigger a pipeline run
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// TagsIgnore are the patterns of tags which never trigger a pipeline run
+	// +optional
+	TagsIgnore []string `json:"tagsIgnore,omitempty"`
+
+	// Paths are the patterns of which at least one changed file must match to trigger a pipeline run.
+	// Path filters apply to push, pull request and merge request events only.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+
+	// PathsIgnore are the patterns of files which are not considered as changes.
+	// The event is skipped when all changed files match these patterns.
+	// +optional
+	PathsIgnore []string `json:"pathsIgnore,omitempty"`
+}
+
+// ParamMapping maps event data to a pipelinerun param.
+// Exactly one of Variable or JSONPath should be given.
+type ParamMapping struct {
+	// Name of the pipelinerun param
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Variable is the name of a trigger variable used as value (ex. BRANCH, COMMIT_SHA)
+	// +optional
+	Variable string `json:"variable,omitempty"`
+
+	// JSONPath is a jsonpath expression evaluated against the webhook payload
+	// used as value (ex. {.repository.full_name})
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+}
+
+// GitHookTrigger selects the pipelinerun run for matching events.
+// One of PipelineRef, RunSpec and PipelineRunSpec must be given.
+type GitHookTrigger struct {
+	// Name identifies the trigger in pipelinerun labels and messages
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// EventTypes are the types of event matching the trigger. Defaults to all event types of the GitHook.
+	// +optional
+	EventTypes []GitEvent `json:"eventTypes,omitempty"`
+
+	// Filters restricts the branches, tags and changed paths matching the trigger
+	// in addition to filters of the GitHook
+	// +optional
+	Filters *GitHookFilters `json:"filters,omitempty"`
+
+	// Expression is a go template rendered with .Vars and .Payload of the event.
+	// The trigger matches when it renders "true" (ex. {{ eq .Vars.BRANCH "main" }}).
+	// +optional
+	Expression string `json:"expression,omitempty"`
+
+	// PipelineRef is the pipeline to be run. It replaces pipelineRef and pipelineSpec of
+	// RunSpec or PipelineRunSpec so both can be given to set the other fields of the pipelinerun.
+	// +optional
+	PipelineRef *tektonv1alpha1.PipelineRef `json:"pipelineRef,omitempty"`
+
+	// RunSpec is a tekton.dev/v1alpha1 pipelinerun spec to be run for matching events
+	// +optional
+	RunSpec tektonv1alpha1.PipelineRunSpec `json:"runspec,omitempty"`
+
+	// PipelineRunSpec is the pipelinerun spec of PipelineAPIVersion tekton.dev/v1beta1
+	// or tekton.dev/v1 to be run for matching events
+	// +optional
+	PipelineRunSpec *runtime.RawExtension `json:"pipelineRunSpec,omitempty"`
+}
 
 // GitHookSpec defines the desired state of GitHook
 type GitHookSpec struct {
@@ -56,29 +376,43 @@ type GitHookSpec struct {
 
 	// ServiceAccountName holds the name of the Kubernetes service account
 	// as which the underlying K8s resources should be run. If unspecified
-	// this will default to the "default" service account for the namespace
-	// in which the GitHook exists.
+	// this will default to the "pipeline-runner" service account for the namespace
+	// in which the GitHook exists (see config/tektonrole.yaml).
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
 	// ProjectUrl is the url of the git project for which we are interested
-	// to receive events from.
+	// to receive events from. It is the url of the organization or group
+	// when Scope is organization.
 	// Examples:
 	//   https://gitlab.com/pongsatt/githook
 	// +kubebuilder:validation:MinLength=1
 	ProjectURL string `json:"projectUrl"`
 
+	// Scope is the level webhook is registered on. "project" (default) registers
+	// webhook on the project. "organization" registers a single webhook on the
+	// organization (github, gitea) or group (gitlab) which receives events of all its projects.
+	// +optional
+	Scope HookScope `json:"scope,omitempty"`
+
 	// GitProvder is the name of the git source in which we would like register webhook
 	GitProvider GitProvider `json:"gitProvider"`
 
-	// EventType is the type of event to receive from Gogs. These
-	// correspond to supported events to the add project hook
-	// +kubebuilder:validation:MinItems=1
-	EventTypes []gitEvent `json:"eventTypes"`
+	// EventType is the type of event to receive from the git provider.
+	// Event types not supported by the git provider are rejected.
+	// Defaults to push and pull_request.
+	// +optional
+	EventTypes []GitEvent `json:"eventTypes,omitempty"`
 
 	// AccessToken is the Kubernetes secret containing the Gogs
-	// access token
-	AccessToken SecretValueFromSource `json:"accessToken"`
+	// access token. Required unless GithubApp is given.
+	// +optional
+	AccessToken SecretValueFromSource `json:"accessToken,omitempty"`
+
+	// GithubApp authenticates as github app installation instead of using access token.
+	// Only supported by github provider.
+	// +optional
+	GithubApp *GithubAppCredentials `json:"githubApp,omitempty"`
 
 	// SecretToken is the Kubernetes secret containing the Gogs
 	// secret token
@@ -88,8 +422,77 @@ type GitHookSpec struct {
 	// +optional
 	SslVerify bool `json:"sslverify,omitempty"`
 
-	// RunSpec is a tekton pipelinerun spec to be run when events triggered
-	RunSpec tektonv1alpha1.PipelineRunSpec `json:"runspec"`
+	// CABundle is the source of PEM encoded CA certificates trusted in addition to
+	// the system ones when calling the git provider api (ex. self-hosted server with private CA)
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+
+	// Filters restricts the branches, tags and changed paths which trigger a pipeline run.
+	// When only branch filters are given, tag events are skipped and vice versa.
+	// +optional
+	Filters *GitHookFilters `json:"filters,omitempty"`
+
+	// RenderMode is the way runspec is rendered with event data.
+	// "vars" (default) replaces $VARIABLE with event data.
+	// "template" renders each string value as a go template with .Vars and .Payload.
+	// +optional
+	RenderMode RenderMode `json:"renderMode,omitempty"`
+
+	// Params are appended to params of the pipelinerun with values taken from the event.
+	// A param with the same name in runspec is replaced.
+	// +optional
+	Params []ParamMapping `json:"params,omitempty"`
+
+	// Routes select the pipelinerun spec by repository name of the event.
+	// The first matching route is used. Events of repositories matching no route run RunSpec.
+	// +optional
+	Routes []RepositoryRoute `json:"routes,omitempty"`
+
+	// Triggers select the pipelineruns created for an event. Every matching trigger,
+	// in order, creates a pipelinerun so an event creates none or many of them.
+	// RunSpec, PipelineRunSpec and Routes are not used when triggers are given.
+	// +optional
+	Triggers []GitHookTrigger `json:"triggers,omitempty"`
+
+	// ConcurrencyPolicy is the way pipelineruns of the same branch or pull request run.
+	// "Allow" (default) runs them concurrently. "CancelPrevious" cancels running pipelineruns
+	// once the new one is created. "Queue" creates the new pipelinerun when running ones finish.
+	// Events of neither branch nor pull request (ex. tags) always run concurrently.
+	// +optional
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+
+	// Retention deletes old pipelineruns created by the GitHook when given.
+	// +optional
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+
+	// CommitStatus reports results of pipelineruns as statuses of the triggering commit
+	// (github, gitlab and gitea) when given.
+	// +optional
+	CommitStatus *CommitStatusReport `json:"commitStatus,omitempty"`
+
+	// PipelineAPIVersion is the api version of pipelineruns created. "tekton.dev/v1alpha1" (default)
+	// runs RunSpec. "tekton.dev/v1beta1" and "tekton.dev/v1" run PipelineRunSpec.
+	// CommitStatus, Retention and ConcurrencyPolicy "Queue" require "tekton.dev/v1alpha1".
+	// +optional
+	PipelineAPIVersion PipelineAPIVersion `json:"pipelineApiVersion,omitempty"`
+
+	// RunSpec is a tekton.dev/v1alpha1 pipelinerun spec to be run when events triggered.
+	// Required unless PipelineAPIVersion is tekton.dev/v1beta1 or tekton.dev/v1 or Triggers are given.
+	// +optional
+	RunSpec tektonv1alpha1.PipelineRunSpec `json:"runspec,omitempty"`
+
+	// Workspaces bind workspaces of every pipelinerun to a fresh volume, replacing
+	// workspaces with the same name in PipelineRunSpec. Only supported by
+	// PipelineAPIVersion tekton.dev/v1beta1 and tekton.dev/v1.
+	// +optional
+	Workspaces []WorkspaceTemplate `json:"workspaces,omitempty"`
+
+	// PipelineRunSpec is the pipelinerun spec of PipelineAPIVersion tekton.dev/v1beta1
+	// or tekton.dev/v1 to be run when events triggered (ex. with workspaces, pipelineSpec
+	// and taskRunTemplate). Params git-url and git-revision are added unless given.
+	// Required for these api versions unless Triggers are given.
+	// +optional
+	PipelineRunSpec *runtime.RawExtension `json:"pipelineRunSpec,omitempty"`
 }
 
 // GitHookStatus defines the observed state of GitHook
@@ -99,9 +502,32 @@ type GitHookStatus struct {
 
 	// ID of the project hook registered with Gogs
 	ID string `json:"Id,omitempty"`
+
+	// Conditions the latest available observations of the GitHook's current state
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the GitHook that was last processed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// WebhookURL is the url of the receiver registered as webhook on the git provider
+	// +optional
+	WebhookURL string `json:"webhookUrl,omitempty"`
+
+	// LastSyncTime is the last time the webhook was created or updated on the git provider
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].reason"
+// +kubebuilder:printcolumn:name="Hook ID",type="string",JSONPath=".status.Id"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.webhookUrl",priority=1
+// +kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=".status.lastSyncTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // GitHook is the Schema for the GitHooks API
 type GitHook struct {
@@ -61,7 +61,7 @@ var _ = Describe("GitHook", func() {
 				Spec: GitHookSpec{
 					ProjectURL:  "http://test.git",
 					GitProvider: Gitlab,
-					EventTypes:  []gitEvent{"push"},
+					EventTypes:  []GitEvent{"push"},
 				}}
 
 			By("creating an API obj")
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/client"
 	"gitlab.com/pongsatt/githook/pkg/githook"
+	"gitlab.com/pongsatt/githook/pkg/model"
 	"gitlab.com/pongsatt/githook/pkg/server"
 	"gitlab.com/pongsatt/githook/pkg/tekton"
 )
@@ -16,16 +20,28 @@ import (
 const (
 	// Environment variable containing the HTTP port
 	envPort = "PORT"
-
-	// EnvSecret environment variable containing git secret token
-	envSecret = "SECRET_TOKEN"
 )
 
 func main() {
-	gitprovider := flag.String("gitprovider", "", "git provider ex. gitlab github")
+	gitprovider := flag.String("gitprovider", "", "git provider ex. gitlab github bitbucket")
 	namespace := flag.String("namespace", "default", "namespace to create pipelinerun")
 	name := flag.String("name", "", "name of the pipelinerun")
 	runSpecJSON := flag.String("runSpecJSON", "", "pipelinerun spec in json format")
+	pipelineAPIVersion := flag.String("pipelineApiVersion", "", "tekton api version of pipelineruns, tekton.dev/v1alpha1, tekton.dev/v1beta1 or tekton.dev/v1")
+	routesJSON := flag.String("routesJSON", "", "pipelinerun specs by repository in json format")
+	triggersJSON := flag.String("triggersJSON", "", "trigger rules selecting pipelineruns of events in json format")
+	renderMode := flag.String("renderMode", "", "how runspec is rendered, vars or template")
+	concurrencyPolicy := flag.String("concurrencyPolicy", "", "how pipelineruns of the same branch or pull request run, Allow, CancelPrevious or Queue")
+	paramsJSON := flag.String("paramsJSON", "", "param mappings in json format")
+	workspacesJSON := flag.String("workspacesJSON", "", "workspace templates in json format")
+	eventTypes := flag.String("eventTypes", "", "comma separated event types of GitHook, all events are handled if empty")
+	filtersJSON := flag.String("filtersJSON", "", "branch, tag and path filters in json format")
+	baseURL := flag.String("baseUrl", "", "base url of the git provider")
+	owner := flag.String("owner", "", "owner of the git project")
+	project := flag.String("project", "", "name of the git project")
+	scope := flag.String("scope", "", "level webhook is registered on, project or organization")
+	githubAppID := flag.Int64("githubAppId", 0, "id of github app used instead of access token")
+	githubInstallationID := flag.Int64("githubInstallationId", 0, "id of github app installation")
 
 	flag.Parse()
 
@@ -46,13 +62,56 @@ func main() {
 		port = "8080"
 	}
 
-	secretToken := os.Getenv(envSecret)
+	secretToken := os.Getenv(model.EnvSecret)
 	if secretToken == "" {
 		log.Fatalf("No secret token given")
 	}
 
 	log.Printf("runSpecJSON is: %q", *runSpecJSON)
 
+	var params []v1alpha1.ParamMapping
+	if *paramsJSON != "" {
+		if err := json.Unmarshal([]byte(*paramsJSON), &params); err != nil {
+			log.Fatalf("cannot parse paramsJSON: %s", err)
+		}
+	}
+
+	var routes []v1alpha1.RepositoryRoute
+	if *routesJSON != "" {
+		if err := json.Unmarshal([]byte(*routesJSON), &routes); err != nil {
+			log.Fatalf("cannot parse routesJSON: %s", err)
+		}
+	}
+
+	var triggers []v1alpha1.GitHookTrigger
+	if *triggersJSON != "" {
+		if err := json.Unmarshal([]byte(*triggersJSON), &triggers); err != nil {
+			log.Fatalf("cannot parse triggersJSON: %s", err)
+		}
+	}
+
+	var workspaces []v1alpha1.WorkspaceTemplate
+	if *workspacesJSON != "" {
+		if err := json.Unmarshal([]byte(*workspacesJSON), &workspaces); err != nil {
+			log.Fatalf("cannot parse workspacesJSON: %s", err)
+		}
+	}
+
+	var filters *v1alpha1.GitHookFilters
+	if *filtersJSON != "" {
+		filters = &v1alpha1.GitHookFilters{}
+		if err := json.Unmarshal([]byte(*filtersJSON), filters); err != nil {
+			log.Fatalf("cannot parse filtersJSON: %s", err)
+		}
+	}
+
+	var gitEvents []v1alpha1.GitEvent
+	if *eventTypes != "" {
+		for _, eventType := range strings.Split(*eventTypes, ",") {
+			gitEvents = append(gitEvents, v1alpha1.GitEvent(eventType))
+		}
+	}
+
 	tektonClient, err := tekton.New()
 
 	if err != nil {
@@ -65,12 +124,51 @@ func main() {
 		log.Fatal(err)
 	}
 
+	hookOptions := &model.HookOptions{
+		AccessToken: os.Getenv(model.EnvAccessToken),
+		BaseURL:     *baseURL,
+		Owner:       *owner,
+		Project:     *project,
+		CABundle:    os.Getenv(model.EnvCABundle),
+
+		Organization: v1alpha1.HookScope(*scope) == v1alpha1.ScopeOrganization,
+	}
+
+	if *githubAppID != 0 {
+		hookOptions.GithubApp = &model.GithubAppOptions{
+			AppID:          *githubAppID,
+			InstallationID: *githubInstallationID,
+			PrivateKey:     os.Getenv(model.EnvGithubAppPrivateKey),
+		}
+	}
+
+	var gitClient *githook.Client
+	if hookOptions.AccessToken != "" || hookOptions.GithubApp != nil {
+		gitClient, err = githook.NewForProvider(v1alpha1.GitProvider(*gitprovider), hookOptions, client.NewGithubAppTokenCache())
+
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	ra := &githook.ReceiveAdapter{
 		TektonClient: tektonClient,
+		GitClient:    gitClient,
+		HookOptions:  hookOptions,
 		HookServer:   hook,
 		Namespace:    *namespace,
 		Name:         *name,
 		RunSpecJSON:  *runSpecJSON,
+		Routes:       routes,
+		RenderMode:   v1alpha1.RenderMode(*renderMode),
+		Params:       params,
+		Filters:      filters,
+		Triggers:     triggers,
+		EventTypes:   gitEvents,
+
+		ConcurrencyPolicy: v1alpha1.ConcurrencyPolicy(*concurrencyPolicy),
+		APIVersion:        v1alpha1.PipelineAPIVersion(*pipelineAPIVersion),
+		Workspaces:        workspaces,
 	}
 
 	addr := fmt.Sprintf(":%s", port)
@@ -88,6 +186,14 @@ func buildHookServer(gitprovider v1alpha1.GitProvider, secretToken string) (gith
 		return server.NewGithubServer(secretToken)
 	case v1alpha1.Gitlab:
 		return server.NewGitlabServer(secretToken)
+	case v1alpha1.Gitea:
+		return server.NewGiteaServer(secretToken)
+	case v1alpha1.Bitbucket:
+		return server.NewBitbucketServer(secretToken)
+	case v1alpha1.BitbucketServer:
+		return server.NewBitbucketServerServer(secretToken)
+	case v1alpha1.AzureDevOps:
+		return server.NewAzureDevOpsServer(secretToken)
 	}
 
 	return nil, fmt.Errorf("provider %s not supported", gitprovider)
@@ -25,6 +25,8 @@ import (
 	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
 	"gitlab.com/pongsatt/githook/controllers"
+	"gitlab.com/pongsatt/githook/pkg/client"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -51,9 +53,12 @@ func init() {
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var maxConcurrentReconciles int
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The maximum number of GitHooks which can be reconciled concurrently.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.Logger(true))
@@ -75,16 +80,67 @@ func main() {
 		os.Exit(1)
 	}
 
+	// installation tokens of github apps are shared by controllers
+	githubAppTokens := client.NewGithubAppTokenCache()
+
 	err = (&controllers.GitHookReconciler{
 		Client:       mgr.GetClient(),
 		Log:          ctrl.Log.WithName("controllers").WithName("GitHook"),
 		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("githook-controller"),
 		WebhookImage: webhookImage,
+
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		GithubAppTokens:         githubAppTokens,
 	}).SetupWithManager(mgr)
 	if err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GitHook")
 		os.Exit(1)
 	}
+
+	tektonClient, err := tekton.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create tekton client")
+		os.Exit(1)
+	}
+
+	// pipelineruns are watched in every api version served by the installed tekton release
+	apiVersions, err := tektonClient.ServedAPIVersions()
+	if err != nil {
+		setupLog.Error(err, "unable to discover tekton api versions")
+		os.Exit(1)
+	}
+
+	for _, apiVersion := range apiVersions {
+		err = (&controllers.PipelineRunReconciler{
+			Client:     mgr.GetClient(),
+			Tekton:     tektonClient,
+			Log:        ctrl.Log.WithName("controllers").WithName("PipelineRun").WithValues("apiVersion", apiVersion),
+			Recorder:   mgr.GetEventRecorderFor("githook-controller"),
+			APIVersion: apiVersion,
+
+			GithubAppTokens: githubAppTokens,
+		}).SetupWithManager(mgr)
+		if err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "PipelineRun", "apiVersion", apiVersion)
+			os.Exit(1)
+		}
+	}
+
+	err = (&controllers.RetentionReconciler{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("controllers").WithName("Retention"),
+		Recorder: mgr.GetEventRecorderFor("githook-controller"),
+	}).SetupWithManager(mgr)
+	if err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Retention")
+		os.Exit(1)
+	}
+
+	// admission webhooks need serving certificates (see config/certmanager)
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		controllers.SetupWebhooksWithManager(mgr)
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -19,7 +19,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
 	"strings"
 	"time"
 
@@ -32,8 +31,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlsource "sigs.k8s.io/controller-runtime/pkg/source"
 
 	"gitlab.com/pongsatt/githook/api/v1alpha1"
 	githookclient "gitlab.com/pongsatt/githook/pkg/client"
@@ -47,6 +50,22 @@ const (
 	finalizerName       = controllerAgentName
 )
 
+// reasons of events recorded on GitHook
+const (
+	reasonSecretNotFound    = "SecretNotFound"
+	reasonServiceCreated    = "ServiceCreated"
+	reasonServiceUpdated    = "ServiceUpdated"
+	reasonServiceDeleted    = "ServiceDeleted"
+	reasonServiceFailed     = "ServiceFailed"
+	reasonWebhookCreated    = "WebhookCreated"
+	reasonWebhookUpdated    = "WebhookUpdated"
+	reasonWebhookDeleted    = "WebhookDeleted"
+	reasonWebhookFailed     = "WebhookFailed"
+	reasonFinalized         = "Finalized"
+	reasonFinalizeFailed    = "FinalizeFailed"
+	reasonUnsupportedEvents = "UnsupportedEventTypes"
+)
+
 func ignoreNotFound(err error) error {
 	if apierrs.IsNotFound(err) {
 		return nil
@@ -67,30 +86,22 @@ type GitHookReconciler struct {
 	client.Client
 	Log          logr.Logger
 	Scheme       *runtime.Scheme
+	Recorder     record.EventRecorder
 	WebhookImage string
-}
 
-func getGitClient(source *v1alpha1.GitHook, options *model.HookOptions) (*githook.Client, error) {
-	var gitClient githook.GitClient
+	// MaxConcurrentReconciles is the maximum number of GitHooks reconciled at the same time
+	MaxConcurrentReconciles int
 
-	switch source.Spec.GitProvider {
-	case v1alpha1.Gogs:
-		gitClient = githookclient.NewGogsClient(options.BaseURL, options.AccessToken)
-	case v1alpha1.Github:
-		gitClient = githookclient.NewGithubClient(options.AccessToken)
-	case v1alpha1.Gitlab:
-		gitClient = githookclient.NewGitlabClient(options.BaseURL, options.AccessToken)
-	default:
-		return nil, fmt.Errorf("git provider %s not support", source.Spec.GitProvider)
-	}
-
-	return githook.New(gitClient, options.BaseURL, options.AccessToken)
+	// GithubAppTokens caches installation tokens of github apps
+	GithubAppTokens *githookclient.GithubAppTokenCache
 }
 
 // +kubebuilder:rbac:groups=tools.pongzt.com,resources=githooks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=tools.pongzt.com,resources=githooks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=serving.knative.dev,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=eventing.knative.dev,resources=channels,verbs=get;list;watch
 
 // Reconcile main reconcile logic
@@ -107,110 +118,135 @@ func (r *GitHookReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, ignoreNotFound(err)
 	}
 
-	source := sourceOrg.DeepCopyObject()
+	source := sourceOrg.DeepCopy()
 
+	var result ctrl.Result
 	var reconcileErr error
 	if sourceOrg.ObjectMeta.DeletionTimestamp == nil {
-		reconcileErr = r.reconcile(source.(*v1alpha1.GitHook))
+		result, reconcileErr = r.reconcile(source)
 	} else {
-		if r.hasFinalizer(source.(*v1alpha1.GitHook).Finalizers) {
-			reconcileErr = r.finalize(source.(*v1alpha1.GitHook))
+		if r.hasFinalizer(source.Finalizers) {
+			reconcileErr = r.finalize(source)
 		}
 	}
+
+	// update overwrites status with the one stored so keep the reconciled one
+	status := source.Status.DeepCopy()
+
 	if err := r.Update(context.Background(), source); err != nil {
 		log.Error(err, "Failed to update")
 		return ctrl.Result{}, err
 	}
-	return ctrl.Result{}, reconcileErr
-}
 
-func parseGitURL(gitURL string) (baseURL string, owner string, project string, err error) {
-	u, err := url.Parse(gitURL)
-	if err != nil {
-		return "", "", "", err
+	if sourceOrg.ObjectMeta.DeletionTimestamp == nil && !apiequality.Semantic.DeepEqual(sourceOrg.Status, *status) {
+		source.Status = *status
+		if err := r.Status().Update(context.Background(), source); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
 	}
 
-	paths := strings.Split(u.Path[1:], "/")
-	baseURL = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
-	owner = paths[0]
-	project = paths[1]
-
-	return baseURL, owner, project, nil
+	return result, reconcileErr
 }
 
-func (r *GitHookReconciler) buildHookFromSource(source *v1alpha1.GitHook) (*model.HookOptions, error) {
-	hookOptions := &model.HookOptions{}
-
-	baseURL, owner, projectName, err := parseGitURL(source.Spec.ProjectURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to process project url to get the project name: " + err.Error())
-	}
+func (r *GitHookReconciler) reconcile(source *v1alpha1.GitHook) (ctrl.Result, error) {
+	log := r.sourceLogger(source)
 
-	hookOptions.BaseURL = baseURL
-	hookOptions.Project = projectName
-	hookOptions.Owner = owner
-	hookOptions.ID = source.Status.ID
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
 
-	for _, event := range source.Spec.EventTypes {
-		hookOptions.Events = append(hookOptions.Events, string(event))
-	}
-	hookOptions.AccessToken, err = r.secretFrom(source.Namespace, source.Spec.AccessToken.SecretKeyRef)
+	hookOptions, err := buildHookFromSource(r.Client, r.Recorder, source)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get accesstoken from secret %s/%s", source.Namespace, source.Spec.AccessToken.SecretKeyRef.Key)
+		if _, ok := err.(secretError); ok {
+			source.Status.MarkSecretsNotResolved("SecretNotFound", "%s", err)
+		} else {
+			source.Status.MarkWebhookNotRegistered("InvalidProjectURL", "%s", err)
+		}
+		return ctrl.Result{}, err
 	}
+	source.Status.MarkSecretsResolved()
 
-	hookOptions.SecretToken, err = r.secretFrom(source.Namespace, source.Spec.SecretToken.SecretKeyRef)
+	hookOptions.Events, err = source.Spec.GitProvider.ProviderEvents(source.Spec.GetEventTypes())
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secret token from secret %s/%s", source.Namespace, source.Spec.AccessToken.SecretKeyRef.Key)
+		source.Status.MarkWebhookNotRegistered(reasonUnsupportedEvents, "%s", err)
+		r.Recorder.Event(source, corev1.EventTypeWarning, reasonUnsupportedEvents, err.Error())
+		return ctrl.Result{}, err
 	}
 
-	return hookOptions, nil
-}
-
-func (r *GitHookReconciler) reconcile(source *v1alpha1.GitHook) error {
-	log := r.sourceLogger(source)
-
-	hookOptions, err := r.buildHookFromSource(source)
+	ksvc, err := r.reconcileWebhookService(source)
 
 	if err != nil {
-		return err
+		source.Status.MarkReceiverNotReady("ServiceFailed", "%s", err)
+		r.Recorder.Event(source, corev1.EventTypeWarning, reasonServiceFailed, err.Error())
+		return ctrl.Result{}, err
 	}
 
-	ksvc, err := r.reconcileWebhookService(source)
-
-	if err != nil {
-		return err
+	if !isKnativeServiceReady(ksvc) {
+		backoff := readyBackoff(ksvc, time.Now())
+		log.Info("webhook service is not ready", "ksvc name", ksvc.Name, "requeue after", backoff)
+		source.Status.MarkReceiverDeploying("ServiceNotReady", "knative service %s is not ready", ksvc.Name)
+		return ctrl.Result{RequeueAfter: backoff}, nil
 	}
+	log.Info("webhook service is ready", "ksvc name", ksvc.Name)
 
 	hookOptions.URL = getWebhookURL(source, ksvc)
+	source.Status.MarkReceiverReady(hookOptions.URL)
 
-	hookID, err := r.reconcileWebhook(source, hookOptions)
+	hookID, synced, err := r.reconcileWebhook(source, hookOptions)
 
 	if err != nil {
-		return err
+		source.Status.MarkWebhookNotRegistered("ProviderError", "%s", err)
+		r.Recorder.Event(source, corev1.EventTypeWarning, reasonWebhookFailed, err.Error())
+		return ctrl.Result{}, err
 	}
-	source.Status.ID = hookID
+
+	if synced {
+		now := metav1.Now()
+		source.Status.LastSyncTime = &now
+	}
+	source.Status.MarkWebhookRegistered(hookID)
 
 	log.Info("add finalizer to the source")
 	r.addFinalizer(source)
-	return nil
+	return ctrl.Result{}, nil
 }
 
-func (r *GitHookReconciler) reconcileWebhook(source *v1alpha1.GitHook, hookOptions *model.HookOptions) (string, error) {
+// eventTypesArg returns event types as comma separated argument of the receive adapter
+func eventTypesArg(gitEvents []v1alpha1.GitEvent) string {
+	eventTypes := []string{}
+	for _, gitEvent := range gitEvents {
+		eventTypes = append(eventTypes, string(gitEvent))
+	}
+
+	return strings.Join(eventTypes, ",")
+}
+
+// hookTarget describes the project or organization webhook is registered on
+func hookTarget(hookOptions *model.HookOptions) string {
+	if hookOptions.Organization {
+		return "organization " + hookOptions.Owner
+	}
+
+	return fmt.Sprintf("project %s/%s", hookOptions.Owner, hookOptions.Project)
+}
+
+// reconcileWebhook ensures webhook registered on git provider. It returns hook id and
+// if the webhook has been created or updated.
+func (r *GitHookReconciler) reconcileWebhook(source *v1alpha1.GitHook, hookOptions *model.HookOptions) (string, bool, error) {
 	log := r.sourceLogger(source)
 
-	gitClient, err := getGitClient(source, hookOptions)
+	gitClient, err := githook.NewForProvider(source.Spec.GitProvider, hookOptions, r.GithubAppTokens)
 
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	exists, changed, err := gitClient.Validate(hookOptions)
 
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	if !exists {
@@ -218,14 +254,11 @@ func (r *GitHookReconciler) reconcileWebhook(source *v1alpha1.GitHook, hookOptio
 		hookID, err := gitClient.Create(hookOptions)
 
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 		log.Info("create new webhook successfully", "project", hookOptions.Project)
-		return hookID, err
-	}
-
-	if err != nil {
-		return "", err
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, reasonWebhookCreated, "Created webhook %s on %s", hookID, hookTarget(hookOptions))
+		return hookID, true, err
 	}
 
 	if changed == true {
@@ -233,16 +266,17 @@ func (r *GitHookReconciler) reconcileWebhook(source *v1alpha1.GitHook, hookOptio
 		hookID, err := gitClient.Update(hookOptions)
 
 		if err != nil {
-			return "", err
+			return "", false, err
 		}
 
 		log.Info("update existing webhook successfully", "project", hookOptions.Project)
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, reasonWebhookUpdated, "Updated webhook %s on %s", hookID, hookTarget(hookOptions))
 
-		return hookID, nil
+		return hookID, true, nil
 	}
 
 	log.Info("webhook exists and updated", "project", hookOptions.Project)
-	return hookOptions.ID, nil
+	return hookOptions.ID, false, nil
 }
 
 func (r *GitHookReconciler) reconcileWebhookService(source *v1alpha1.GitHook) (*servinv1alpha1.Service, error) {
@@ -267,6 +301,7 @@ func (r *GitHookReconciler) reconcileWebhookService(source *v1alpha1.GitHook) (*
 		}
 		ksvc = desiredKsvc
 		log.Info("webhook service created successfully", "name", ksvc.Name)
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, reasonServiceCreated, "Created knative service %s", ksvc.Name)
 	}
 
 	// should update
@@ -284,20 +319,32 @@ func (r *GitHookReconciler) reconcileWebhookService(source *v1alpha1.GitHook) (*
 				return nil, err
 			}
 			log.Info("webhook service template update successfully")
+			r.Recorder.Eventf(source, corev1.EventTypeNormal, reasonServiceUpdated, "Updated knative service %s", ksvc.Name)
 		}
 	}
 
-	log.Info("ensure webhook service is ready", "ksvc name", ksvc.Name)
-	ksvc, err = r.waitForKnativeServiceReady(source)
-	if err != nil {
-		return nil, err
+	return ksvc, nil
+}
+
+func (r *GitHookReconciler) finalize(source *v1alpha1.GitHook) error {
+	if err := r.cleanup(source); err != nil {
+		r.Recorder.Event(source, corev1.EventTypeWarning, reasonFinalizeFailed, err.Error())
+		return err
 	}
-	log.Info("webhook service is ready", "ksvc name", ksvc.Name)
 
-	return ksvc, err
+	if githubApp := source.Spec.GithubApp; githubApp != nil {
+		if baseURL, _, _, err := parseSourceURL(source); err == nil {
+			r.GithubAppTokens.Evict(baseURL, githubApp.AppID, githubApp.InstallationID)
+		}
+	}
+
+	r.removeFinalizer(source)
+	r.Recorder.Event(source, corev1.EventTypeNormal, reasonFinalized, "Removed webhook and knative service")
+	return nil
 }
 
-func (r *GitHookReconciler) finalize(source *v1alpha1.GitHook) error {
+// cleanup removes knative service and webhook owned by the source
+func (r *GitHookReconciler) cleanup(source *v1alpha1.GitHook) error {
 	log := r.Log
 
 	//remove service
@@ -312,15 +359,16 @@ func (r *GitHookReconciler) finalize(source *v1alpha1.GitHook) error {
 			return fmt.Errorf("failed to remove ksvc %s : %s", ksvc.Name, err)
 		}
 		log.Info("remove service %s successfuly", "service", ksvc.Name)
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, reasonServiceDeleted, "Deleted knative service %s", ksvc.Name)
 	}
 
-	hookOptions, err := r.buildHookFromSource(source)
+	hookOptions, err := buildHookFromSource(r.Client, r.Recorder, source)
 
 	if err != nil {
 		return err
 	}
 
-	gitClient, err := getGitClient(source, hookOptions)
+	gitClient, err := githook.NewForProvider(source.Spec.GitProvider, hookOptions, r.GithubAppTokens)
 
 	if err != nil {
 		return err
@@ -337,33 +385,12 @@ func (r *GitHookReconciler) finalize(source *v1alpha1.GitHook) error {
 		if err != nil {
 			return fmt.Errorf("Failed to delete project hook: " + err.Error())
 		}
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, reasonWebhookDeleted, "Deleted webhook %s on %s", hookOptions.ID, hookTarget(hookOptions))
 	}
 
-	r.removeFinalizer(source)
 	return nil
 }
 
-func (r *GitHookReconciler) getSecret(namespace string, secretKeySelector *corev1.SecretKeySelector) (*corev1.Secret, error) {
-	secret := &corev1.Secret{}
-	err := r.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: secretKeySelector.Name}, secret)
-
-	return secret, err
-}
-
-func (r *GitHookReconciler) secretFrom(namespace string, secretKeySelector *corev1.SecretKeySelector) (string, error) {
-	secret, err := r.getSecret(namespace, secretKeySelector)
-
-	if err != nil {
-		return "", err
-	}
-	secretVal, ok := secret.Data[secretKeySelector.Key]
-	if !ok {
-		return "", fmt.Errorf(`key "%s" not found in secret "%s"`, secretKeySelector.Key, secretKeySelector.Name)
-	}
-
-	return string(secretVal), nil
-}
-
 func (r *GitHookReconciler) addFinalizer(source *v1alpha1.GitHook) {
 	source.Finalizers = insertFinalizer(source.Finalizers)
 }
@@ -399,14 +426,38 @@ func (r *GitHookReconciler) generateKnativeServiceObject(source *v1alpha1.GitHoo
 	}
 	env := []corev1.EnvVar{
 		{
-			Name: "SECRET_TOKEN",
+			Name: model.EnvSecret,
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: source.Spec.SecretToken.SecretKeyRef,
 			},
 		},
 	}
 
-	runSpecJSON, err := json.Marshal(source.Spec.RunSpec)
+	if source.Spec.AccessToken.SecretKeyRef != nil {
+		env = append(env, corev1.EnvVar{
+			Name: model.EnvAccessToken,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: source.Spec.AccessToken.SecretKeyRef,
+			},
+		})
+	}
+
+	if source.Spec.CABundle != nil {
+		env = append(env, corev1.EnvVar{
+			Name: model.EnvCABundle,
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: source.Spec.CABundle.ConfigMapKeyRef,
+				SecretKeyRef:    source.Spec.CABundle.SecretKeyRef,
+			},
+		})
+	}
+
+	baseURL, owner, projectName, err := parseSourceURL(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process project url to get the project name: " + err.Error())
+	}
+
+	runSpecJSON, err := runSpecJSONFrom(source)
 	if err != nil {
 		return nil, err
 	}
@@ -415,7 +466,79 @@ func (r *GitHookReconciler) generateKnativeServiceObject(source *v1alpha1.GitHoo
 		fmt.Sprintf("--gitprovider=%s", source.Spec.GitProvider),
 		fmt.Sprintf("--namespace=%s", source.Namespace),
 		fmt.Sprintf("--name=%s", source.Name),
-		fmt.Sprintf("--runSpecJSON=%s", string(runSpecJSON)),
+		fmt.Sprintf("--runSpecJSON=%s", runSpecJSON),
+		fmt.Sprintf("--baseUrl=%s", baseURL),
+		fmt.Sprintf("--owner=%s", owner),
+		fmt.Sprintf("--project=%s", projectName),
+		fmt.Sprintf("--eventTypes=%s", eventTypesArg(source.Spec.GetEventTypes())),
+	}
+
+	if githubApp := source.Spec.GithubApp; githubApp != nil {
+		env = append(env, corev1.EnvVar{
+			Name: model.EnvGithubAppPrivateKey,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: githubApp.PrivateKey.SecretKeyRef,
+			},
+		})
+		containerArgs = append(containerArgs,
+			fmt.Sprintf("--githubAppId=%d", githubApp.AppID),
+			fmt.Sprintf("--githubInstallationId=%d", githubApp.InstallationID))
+	}
+
+	if source.Spec.Scope != "" {
+		containerArgs = append(containerArgs, fmt.Sprintf("--scope=%s", source.Spec.Scope))
+	}
+
+	if len(source.Spec.Routes) > 0 {
+		routesJSON, err := json.Marshal(source.Spec.Routes)
+		if err != nil {
+			return nil, err
+		}
+		containerArgs = append(containerArgs, fmt.Sprintf("--routesJSON=%s", string(routesJSON)))
+	}
+
+	if len(source.Spec.Triggers) > 0 {
+		triggersJSON, err := json.Marshal(source.Spec.Triggers)
+		if err != nil {
+			return nil, err
+		}
+		containerArgs = append(containerArgs, fmt.Sprintf("--triggersJSON=%s", string(triggersJSON)))
+	}
+
+	if source.Spec.RenderMode != "" {
+		containerArgs = append(containerArgs, fmt.Sprintf("--renderMode=%s", source.Spec.RenderMode))
+	}
+
+	if source.Spec.PipelineAPIVersion != "" {
+		containerArgs = append(containerArgs, fmt.Sprintf("--pipelineApiVersion=%s", source.Spec.PipelineAPIVersion))
+	}
+
+	if source.Spec.ConcurrencyPolicy != "" {
+		containerArgs = append(containerArgs, fmt.Sprintf("--concurrencyPolicy=%s", source.Spec.ConcurrencyPolicy))
+	}
+
+	if len(source.Spec.Params) > 0 {
+		paramsJSON, err := json.Marshal(source.Spec.Params)
+		if err != nil {
+			return nil, err
+		}
+		containerArgs = append(containerArgs, fmt.Sprintf("--paramsJSON=%s", string(paramsJSON)))
+	}
+
+	if len(source.Spec.Workspaces) > 0 {
+		workspacesJSON, err := json.Marshal(source.Spec.Workspaces)
+		if err != nil {
+			return nil, err
+		}
+		containerArgs = append(containerArgs, fmt.Sprintf("--workspacesJSON=%s", string(workspacesJSON)))
+	}
+
+	if source.Spec.Filters != nil {
+		filtersJSON, err := json.Marshal(source.Spec.Filters)
+		if err != nil {
+			return nil, err
+		}
+		containerArgs = append(containerArgs, fmt.Sprintf("--filtersJSON=%s", string(filtersJSON)))
 	}
 
 	ksvc := &servinv1alpha1.Service{
@@ -469,22 +592,6 @@ func (r *GitHookReconciler) getOwnedKnativeService(source *v1alpha1.GitHook) (*s
 	return &list.Items[0], nil
 }
 
-func (r *GitHookReconciler) waitForKnativeServiceReady(source *v1alpha1.GitHook) (*servinv1alpha1.Service, error) {
-	for attempts := 0; attempts < 4; attempts++ {
-		ksvc, err := r.getOwnedKnativeService(source)
-		if err != nil {
-			return nil, err
-		}
-		routeCondition := ksvc.Status.GetCondition(servinv1alpha1.ServiceConditionRoutesReady)
-		receiveAdapterAddr := ksvc.Status.Address
-		if routeCondition != nil && routeCondition.Status == corev1.ConditionTrue && receiveAdapterAddr != nil {
-			return ksvc, nil
-		}
-		time.Sleep(2000 * time.Millisecond)
-	}
-	return nil, fmt.Errorf("Failed to get service to be in ready state")
-}
-
 // SetupWithManager setups controller with manager
 func (r *GitHookReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(&servinv1alpha1.Service{}, jobOwnerKey, func(rawObj runtime.Object) []string {
@@ -505,8 +612,21 @@ func (r *GitHookReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.GitHook{}).
-		Owns(&servinv1alpha1.Service{}).
-		Complete(r)
+	c, err := controller.New("githook", mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.Watch(&ctrlsource.Kind{Type: &v1alpha1.GitHook{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	// requeue the owner when knative service changes ex. becomes ready
+	return c.Watch(&ctrlsource.Kind{Type: &servinv1alpha1.Service{}}, &handler.EnqueueRequestForOwner{
+		OwnerType:    &v1alpha1.GitHook{},
+		IsController: true,
+	})
 }
@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/githook"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+// paths of admission webhooks of GitHook
+const (
+	mutateGitHookPath   = "/mutate-tools-pongzt-com-v1alpha1-githook"
+	validateGitHookPath = "/validate-tools-pongzt-com-v1alpha1-githook"
+)
+
+// +kubebuilder:webhook:path=/mutate-tools-pongzt-com-v1alpha1-githook,mutating=true,failurePolicy=fail,groups=tools.pongzt.com,resources=githooks,verbs=create;update,versions=v1alpha1,name=mgithook.tools.pongzt.com
+// +kubebuilder:webhook:path=/validate-tools-pongzt-com-v1alpha1-githook,mutating=false,failurePolicy=fail,groups=tools.pongzt.com,resources=githooks,verbs=create;update,versions=v1alpha1,name=vgithook.tools.pongzt.com
+
+// SetupWebhooksWithManager registers defaulting and validating admission webhooks of GitHook
+// with the webhook server of manager
+func SetupWebhooksWithManager(mgr ctrl.Manager) {
+	server := mgr.GetWebhookServer()
+	server.Register(mutateGitHookPath, &admission.Webhook{Handler: &GitHookDefaulter{}})
+	server.Register(validateGitHookPath, &admission.Webhook{Handler: &GitHookValidator{}})
+}
+
+// GitHookDefaulter sets default values of unspecified fields of GitHooks
+type GitHookDefaulter struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder of admission requests
+func (d *GitHookDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// Handle patches GitHook of admission request with default values
+func (d *GitHookDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	source := &v1alpha1.GitHook{}
+	if err := d.decoder.Decode(req, source); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// patches of defaulted fields only, leaving the fields decoding adds to the request as is
+	original, err := json.Marshal(source)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	defaultGitHook(source)
+
+	defaulted, err := json.Marshal(source)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(original, defaulted)
+}
+
+// GitHookValidator rejects GitHooks the controller cannot reconcile
+type GitHookValidator struct {
+	decoder *admission.Decoder
+}
+
+// InjectDecoder injects the decoder of admission requests
+func (v *GitHookValidator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+// Handle denies admission request of invalid GitHook
+func (v *GitHookValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	source := &v1alpha1.GitHook{}
+	if err := v.decoder.Decode(req, source); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// finalizers of GitHooks being deleted are removed even if they became invalid
+	if source.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+
+	if req.Operation == admissionv1beta1.Update && len(req.OldObject.Raw) > 0 {
+		old := &v1alpha1.GitHook{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		// metadata only updates, ex. of finalizers, do not change what is reconciled
+		if apiequality.Semantic.DeepEqual(old.Spec, source.Spec) {
+			return admission.Allowed("")
+		}
+	}
+
+	if errs := validateGitHook(source); len(errs) > 0 {
+		return admission.Denied(strings.Join(errs, "; "))
+	}
+
+	return admission.Allowed("")
+}
+
+// defaultGitHook sets default service account and event types of GitHook
+func defaultGitHook(source *v1alpha1.GitHook) {
+	source.Spec.SetDefaults()
+}
+
+// validateGitHook returns the reasons why GitHook cannot be reconciled
+func validateGitHook(source *v1alpha1.GitHook) []string {
+	errs := validateProjectURL(source)
+	errs = append(errs, validateEventTypes(source)...)
+	errs = append(errs, validateSecretRefs(source)...)
+	errs = append(errs, validateRunSpecs(source)...)
+	errs = append(errs, validatePatterns(source)...)
+
+	return errs
+}
+
+// validateProjectURL checks if project url of GitHook is an absolute url which can be
+// split into owner and project, or organization when webhook is registered on organization
+func validateProjectURL(source *v1alpha1.GitHook) []string {
+	u, err := url.Parse(source.Spec.ProjectURL)
+	if err != nil {
+		return []string{fmt.Sprintf("spec.projectUrl: %s", err)}
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return []string{fmt.Sprintf("spec.projectUrl: %q must be an absolute http or https url", source.Spec.ProjectURL)}
+	}
+
+	if _, _, _, err := parseSourceURL(source); err != nil {
+		return []string{fmt.Sprintf("spec.projectUrl: %s", err)}
+	}
+
+	return nil
+}
+
+// validateEventTypes checks if event types of GitHook and its triggers are supported by git provider
+func validateEventTypes(source *v1alpha1.GitHook) []string {
+	provider := source.Spec.GitProvider
+	errs := []string{}
+
+	check := func(path string, eventTypes []v1alpha1.GitEvent) {
+		for i, eventType := range eventTypes {
+			if !provider.SupportsEvent(eventType) {
+				errs = append(errs, fmt.Sprintf("%s[%d]: event type %s is not supported by provider %s, supported event types are %v",
+					path, i, eventType, provider, provider.SupportedEvents()))
+			}
+		}
+	}
+
+	check("spec.eventTypes", source.Spec.EventTypes)
+	for i, trigger := range source.Spec.Triggers {
+		check(fmt.Sprintf("spec.triggers[%d].eventTypes", i), trigger.EventTypes)
+	}
+
+	return errs
+}
+
+// validateSecretKeyRef checks if secret key ref names secret and key
+func validateSecretKeyRef(path string, ref *corev1.SecretKeySelector) []string {
+	if ref == nil {
+		return []string{fmt.Sprintf("%s: secret key ref is required", path)}
+	}
+
+	errs := []string{}
+	if ref.Name == "" {
+		errs = append(errs, fmt.Sprintf("%s.name: secret name is required", path))
+	}
+	if ref.Key == "" {
+		errs = append(errs, fmt.Sprintf("%s.key: secret key is required", path))
+	}
+
+	return errs
+}
+
+// validateSecretRefs checks if secrets holding secret token, access token or github app
+// private key, and CA bundle of GitHook are referred to
+func validateSecretRefs(source *v1alpha1.GitHook) []string {
+	errs := validateSecretKeyRef("spec.secretToken.secretKeyRef", source.Spec.SecretToken.SecretKeyRef)
+
+	if githubApp := source.Spec.GithubApp; githubApp != nil {
+		if source.Spec.GitProvider != v1alpha1.Github {
+			errs = append(errs, fmt.Sprintf("spec.githubApp: github app is not supported by provider %s", source.Spec.GitProvider))
+		}
+		errs = append(errs, validateSecretKeyRef("spec.githubApp.privateKey.secretKeyRef", githubApp.PrivateKey.SecretKeyRef)...)
+	} else {
+		errs = append(errs, validateSecretKeyRef("spec.accessToken.secretKeyRef", source.Spec.AccessToken.SecretKeyRef)...)
+	}
+
+	if caBundle := source.Spec.CABundle; caBundle != nil {
+		switch {
+		case caBundle.ConfigMapKeyRef != nil && caBundle.SecretKeyRef != nil:
+			errs = append(errs, "spec.caBundle: only one of configMapKeyRef and secretKeyRef can be given")
+		case caBundle.SecretKeyRef != nil:
+			errs = append(errs, validateSecretKeyRef("spec.caBundle.secretKeyRef", caBundle.SecretKeyRef)...)
+		case caBundle.ConfigMapKeyRef == nil:
+			errs = append(errs, "spec.caBundle: one of configMapKeyRef and secretKeyRef is required")
+		case caBundle.ConfigMapKeyRef.Name == "" || caBundle.ConfigMapKeyRef.Key == "":
+			errs = append(errs, "spec.caBundle.configMapKeyRef: configmap name and key are required")
+		}
+	}
+
+	return errs
+}
+
+// runSpecOf returns the pipelinerun spec in json format given either as
+// tekton.dev/v1alpha1 runspec or as pipelineRunSpec of newer api versions
+func runSpecOf(runSpec interface{}, pipelineRunSpec *runtime.RawExtension) (string, string, error) {
+	if pipelineRunSpec != nil && len(pipelineRunSpec.Raw) > 0 {
+		spec := map[string]interface{}{}
+		if err := json.Unmarshal(pipelineRunSpec.Raw, &spec); err != nil {
+			return "pipelineRunSpec", "", fmt.Errorf("pipelinerun spec must be an object: %s", err)
+		}
+		return "pipelineRunSpec", string(pipelineRunSpec.Raw), nil
+	}
+
+	runSpecJSON, err := json.Marshal(runSpec)
+	return "runspec", string(runSpecJSON), err
+}
+
+// validatePatterns checks if patterns of filters and routes of GitHook and its triggers compile
+func validatePatterns(source *v1alpha1.GitHook) []string {
+	errs := []string{}
+
+	check := func(path string, patterns []string) {
+		for i, pattern := range patterns {
+			if _, err := githook.CompilePattern(pattern); err != nil {
+				errs = append(errs, fmt.Sprintf("%s[%d]: %s", path, i, err))
+			}
+		}
+	}
+
+	checkFilters := func(path string, filters *v1alpha1.GitHookFilters) {
+		if filters == nil {
+			return
+		}
+
+		check(path+".branches", filters.Branches)
+		check(path+".branchesIgnore", filters.BranchesIgnore)
+		check(path+".tags", filters.Tags)
+		check(path+".tagsIgnore", filters.TagsIgnore)
+		check(path+".paths", filters.Paths)
+		check(path+".pathsIgnore", filters.PathsIgnore)
+	}
+
+	checkFilters("spec.filters", source.Spec.Filters)
+	for i, route := range source.Spec.Routes {
+		check(fmt.Sprintf("spec.routes[%d].repositories", i), route.Repositories)
+	}
+	for i, trigger := range source.Spec.Triggers {
+		checkFilters(fmt.Sprintf("spec.triggers[%d].filters", i), trigger.Filters)
+	}
+
+	return errs
+}
+
+// validateRunSpecs checks if pipelinerun specs of GitHook, its routes and triggers
+// can be used for its pipeline api version and parse with its render mode
+func validateRunSpecs(source *v1alpha1.GitHook) []string {
+	if _, err := runSpecJSONFrom(source); err != nil {
+		return []string{fmt.Sprintf("spec: %s", err)}
+	}
+
+	errs := []string{}
+
+	check := func(path string, runSpec interface{}, pipelineRunSpec *runtime.RawExtension) {
+		field, runSpecJSON, err := runSpecOf(runSpec, pipelineRunSpec)
+
+		if err == nil && source.Spec.RenderMode == v1alpha1.RenderTemplate {
+			err = tekton.ParseTemplates(runSpecJSON)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s.%s: %s", path, field, err))
+		}
+	}
+
+	if len(source.Spec.Triggers) == 0 {
+		check("spec", source.Spec.RunSpec, source.Spec.PipelineRunSpec)
+
+		for i, route := range source.Spec.Routes {
+			check(fmt.Sprintf("spec.routes[%d]", i), route.RunSpec, route.PipelineRunSpec)
+		}
+	}
+
+	for i, trigger := range source.Spec.Triggers {
+		path := fmt.Sprintf("spec.triggers[%d]", i)
+		check(path, trigger.RunSpec, trigger.PipelineRunSpec)
+
+		if trigger.Expression != "" {
+			if err := tekton.ParseExpression(trigger.Expression); err != nil {
+				errs = append(errs, fmt.Sprintf("%s.expression: %s", path, err))
+			}
+		}
+	}
+
+	return errs
+}
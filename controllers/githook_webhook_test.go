@@ -0,0 +1,298 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+)
+
+func secretKeyRef(name, key string) v1alpha1.SecretValueFromSource {
+	return v1alpha1.SecretValueFromSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key},
+	}
+}
+
+func validGitHook() *v1alpha1.GitHook {
+	return &v1alpha1.GitHook{
+		Spec: v1alpha1.GitHookSpec{
+			ProjectURL:  "https://gitlab.com/pongsatt/githook",
+			GitProvider: v1alpha1.Gitlab,
+			EventTypes:  []v1alpha1.GitEvent{"push", "pull_request"},
+			AccessToken: secretKeyRef("gitsecret", "accessToken"),
+			SecretToken: secretKeyRef("gitsecret", "secretToken"),
+			RunSpec:     tektonv1alpha1.PipelineRunSpec{PipelineRef: tektonv1alpha1.PipelineRef{Name: "build"}},
+		},
+	}
+}
+
+func TestValidateGitHook(t *testing.T) {
+	tests := []struct {
+		update func(source *v1alpha1.GitHook)
+		err    string
+	}{
+		{
+			update: func(source *v1alpha1.GitHook) {},
+		},
+		{
+			update: func(source *v1alpha1.GitHook) { source.Spec.ProjectURL = "https://gitlab.com/pongsatt" },
+			err:    "spec.projectUrl: project url https://gitlab.com/pongsatt must contain owner and project name",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) { source.Spec.ProjectURL = "gitlab.com/pongsatt/githook" },
+			err:    `spec.projectUrl: "gitlab.com/pongsatt/githook" must be an absolute http or https url`,
+		},
+		{
+			update: func(source *v1alpha1.GitHook) { source.Spec.EventTypes = []v1alpha1.GitEvent{"push", "create"} },
+			err:    "spec.eventTypes[1]: event type create is not supported by provider gitlab",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.EventTypes = []v1alpha1.GitEvent{"tag_push", "pipeline", "wiki"}
+			},
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.ProjectURL = "https://github.com/pongsatt/githook"
+				source.Spec.GitProvider = v1alpha1.Github
+				source.Spec.Triggers = []v1alpha1.GitHookTrigger{
+					{Name: "ci", EventTypes: []v1alpha1.GitEvent{"pipeline"}, PipelineRef: &tektonv1alpha1.PipelineRef{Name: "build"}},
+				}
+			},
+			err: "spec.triggers[0].eventTypes[0]: event type pipeline is not supported by provider github",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) { source.Spec.SecretToken = v1alpha1.SecretValueFromSource{} },
+			err:    "spec.secretToken.secretKeyRef: secret key ref is required",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) { source.Spec.AccessToken = secretKeyRef("gitsecret", "") },
+			err:    "spec.accessToken.secretKeyRef.key: secret key is required",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.AccessToken = v1alpha1.SecretValueFromSource{}
+				source.Spec.GithubApp = &v1alpha1.GithubAppCredentials{AppID: 1, InstallationID: 2, PrivateKey: secretKeyRef("app", "key")}
+			},
+			err: "spec.githubApp: github app is not supported by provider gitlab",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.PipelineAPIVersion = v1alpha1.PipelineV1beta1
+				source.Spec.PipelineRunSpec = &runtime.RawExtension{Raw: []byte(`["build"]`)}
+			},
+			err: "spec.pipelineRunSpec: pipelinerun spec must be an object",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) { source.Spec.PipelineAPIVersion = v1alpha1.PipelineV1 },
+			err:    "spec: pipelineRunSpec is required for pipeline api version tekton.dev/v1",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.PipelineAPIVersion = v1alpha1.PipelineV1
+				source.Spec.PipelineRunSpec = &runtime.RawExtension{Raw: []byte(`{"pipelineRef":{"name":"build"}}`)}
+				source.Spec.Retention = &v1alpha1.RetentionPolicy{}
+				source.Spec.CommitStatus = &v1alpha1.CommitStatusReport{}
+				source.Spec.ConcurrencyPolicy = v1alpha1.ConcurrencyQueue
+			},
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.RenderMode = v1alpha1.RenderTemplate
+				source.Spec.RunSpec.ServiceAccount = "{{ .Vars.BRANCH "
+			},
+			err: "spec.runspec: failed to parse template at runspec.serviceAccount",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.Triggers = []v1alpha1.GitHookTrigger{
+					{Name: "deploy", Expression: "{{ eq .Vars.BRANCH ", PipelineRef: &tektonv1alpha1.PipelineRef{Name: "deploy"}},
+				}
+			},
+			err: "spec.triggers[0].expression: failed to parse expression",
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.Filters = &v1alpha1.GitHookFilters{Branches: []string{"release/**", "/^release-[0-9]+$/"}, PathsIgnore: []string{"/docs/(/"}}
+			},
+			err: `spec.filters.pathsIgnore[0]: invalid pattern "/docs/(/"`,
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.Triggers = []v1alpha1.GitHookTrigger{
+					{Name: "deploy", Filters: &v1alpha1.GitHookFilters{Tags: []string{"v*", "/v[0-9/"}}, PipelineRef: &tektonv1alpha1.PipelineRef{Name: "deploy"}},
+				}
+			},
+			err: `spec.triggers[0].filters.tags[1]: invalid pattern "/v[0-9/"`,
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.Routes = []v1alpha1.RepositoryRoute{{Repositories: []string{"/app-(api/"}, RunSpec: source.Spec.RunSpec}}
+			},
+			err: `spec.routes[0].repositories[0]: invalid pattern "/app-(api/"`,
+		},
+		{
+			update: func(source *v1alpha1.GitHook) {
+				source.Spec.Filters = &v1alpha1.GitHookFilters{Branches: []string{"feature/*", "/^(main|release-.+)$/"}}
+			},
+		},
+	}
+
+	for i, test := range tests {
+		source := validGitHook()
+		test.update(source)
+
+		errs := strings.Join(validateGitHook(source), "; ")
+
+		if test.err == "" && errs != "" {
+			t.Fatalf("case %d: unexpected errors %s", i, errs)
+		}
+
+		if !strings.Contains(errs, test.err) {
+			t.Fatalf("case %d: expected error %q but got %q", i, test.err, errs)
+		}
+	}
+}
+
+func TestGitHookDefaulter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaulter := &GitHookDefaulter{}
+	defaulter.InjectDecoder(decoder)
+
+	// updates sent by the controller leave out sslverify disabled before
+	disabled := validGitHook()
+	disabled.Spec.SetDefaults()
+	disabled.Spec.SslVerify = false
+	disabledSpec, err := json.Marshal(disabled.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		operation       admissionv1beta1.Operation
+		spec            string
+		expectedPatches map[string]bool
+	}{
+		{
+			operation: admissionv1beta1.Create,
+			spec:      `{"gitProvider":"github","projectUrl":"https://github.com/pongsatt/githook"}`,
+			expectedPatches: map[string]bool{
+				"/spec/serviceAccountName": true,
+				"/spec/eventTypes":         true,
+			},
+		},
+		{
+			operation:       admissionv1beta1.Create,
+			spec:            `{"gitProvider":"github","serviceAccountName":"runner","eventTypes":["release"],"sslverify":false}`,
+			expectedPatches: map[string]bool{},
+		},
+		{
+			operation:       admissionv1beta1.Update,
+			spec:            string(disabledSpec),
+			expectedPatches: map[string]bool{},
+		},
+	}
+
+	for i, test := range tests {
+		raw := []byte(`{"apiVersion":"tools.pongzt.com/v1alpha1","kind":"GitHook","metadata":{"name":"sample"},"spec":` + test.spec + `}`)
+		response := defaulter.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: test.operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		}})
+
+		if !response.Allowed {
+			t.Fatalf("case %d: expected request to be allowed but got %v", i, response.Result)
+		}
+
+		patched := map[string]bool{}
+		for _, patch := range response.Patches {
+			patched[patch.Path] = true
+		}
+
+		for path := range test.expectedPatches {
+			if !patched[path] {
+				t.Fatalf("case %d: expected %s to be defaulted but got %v", i, path, response.Patches)
+			}
+		}
+
+		if len(patched) != len(test.expectedPatches) {
+			t.Fatalf("case %d: expected %d patches but got %v", i, len(test.expectedPatches), response.Patches)
+		}
+	}
+}
+
+func TestGitHookValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	v1alpha1.AddToScheme(scheme)
+
+	decoder, err := admission.NewDecoder(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := &GitHookValidator{}
+	validator.InjectDecoder(decoder)
+
+	invalidSpec := `{"gitProvider":"github"}`
+	object := func(metadata, spec string) runtime.RawExtension {
+		return runtime.RawExtension{Raw: []byte(`{"apiVersion":"tools.pongzt.com/v1alpha1","kind":"GitHook","metadata":` + metadata + `,"spec":` + spec + `}`)}
+	}
+
+	tests := []struct {
+		operation admissionv1beta1.Operation
+		object    runtime.RawExtension
+		oldObject runtime.RawExtension
+		allowed   bool
+	}{
+		{
+			operation: admissionv1beta1.Create,
+			object:    object(`{"name":"sample"}`, invalidSpec),
+			allowed:   false,
+		},
+		{
+			operation: admissionv1beta1.Update,
+			object:    object(`{"name":"sample","deletionTimestamp":"2019-01-01T00:00:00Z","finalizers":["githook-controller"]}`, invalidSpec),
+			oldObject: object(`{"name":"sample","finalizers":["githook-controller"]}`, invalidSpec),
+			allowed:   true,
+		},
+		{
+			operation: admissionv1beta1.Update,
+			object:    object(`{"name":"sample"}`, invalidSpec),
+			oldObject: object(`{"name":"sample","finalizers":["githook-controller"]}`, invalidSpec),
+			allowed:   true,
+		},
+		{
+			operation: admissionv1beta1.Update,
+			object:    object(`{"name":"sample"}`, `{"gitProvider":"gitlab"}`),
+			oldObject: object(`{"name":"sample"}`, invalidSpec),
+			allowed:   false,
+		},
+	}
+
+	for i, test := range tests {
+		response := validator.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: test.operation,
+			Object:    test.object,
+			OldObject: test.oldObject,
+		}})
+
+		if response.Allowed != test.allowed {
+			t.Fatalf("case %d: expected allowed %v but got %v", i, test.allowed, response.Result)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+)
+
+const (
+	// githubOrgsPath and gitlabGroupsPath optionally precede organization and group
+	// in organization urls ex. https://gitlab.com/groups/mygroup
+	githubOrgsPath   = "orgs"
+	gitlabGroupsPath = "groups"
+
+	// azureGitPath separates project and repository in azure devops repository urls
+	azureGitPath = "_git"
+
+	// bitbucketServerProjectsPath and bitbucketServerReposPath precede project key and
+	// repository slug in bitbucket server repository urls
+	bitbucketServerProjectsPath = "projects"
+	bitbucketServerReposPath    = "repos"
+)
+
+// parseGitURL splits project url into base url of git provider, owner and project name.
+// Azure devops urls (https://dev.azure.com/org/project/_git/repo) are split into
+// organization url, project and repository. Bitbucket server urls
+// (https://host/projects/KEY/repos/slug) are split into server url, project key and
+// repository slug.
+func parseGitURL(provider v1alpha1.GitProvider, gitURL string) (baseURL string, owner string, project string, err error) {
+	u, err := url.Parse(gitURL)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	paths := strings.Split(strings.Trim(u.Path, "/"), "/")
+	baseURL = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+
+	switch provider {
+	case v1alpha1.AzureDevOps:
+		for i, path := range paths {
+			if path == azureGitPath && i > 0 && i+1 < len(paths) {
+				if i > 1 {
+					baseURL = baseURL + "/" + strings.Join(paths[:i-1], "/")
+				}
+				return baseURL, paths[i-1], paths[i+1], nil
+			}
+		}
+
+		return "", "", "", fmt.Errorf("project url %s must be like https://dev.azure.com/<organization>/<project>/_git/<repository>", gitURL)
+	case v1alpha1.BitbucketServer:
+		for i, path := range paths {
+			if path == bitbucketServerProjectsPath && i+3 < len(paths) && paths[i+2] == bitbucketServerReposPath {
+				if i > 0 {
+					baseURL = baseURL + "/" + strings.Join(paths[:i], "/")
+				}
+				return baseURL, paths[i+1], paths[i+3], nil
+			}
+		}
+
+		return "", "", "", fmt.Errorf("project url %s must be like https://<server>/projects/<key>/repos/<repository>", gitURL)
+	}
+
+	if len(paths) < 2 || paths[0] == "" || paths[1] == "" {
+		return "", "", "", fmt.Errorf("project url %s must contain owner and project name", gitURL)
+	}
+
+	return baseURL, paths[0], strings.TrimSuffix(paths[1], ".git"), nil
+}
+
+// parseGitOrgURL splits organization or group url into base url of git provider and
+// organization. Gitlab subgroups are kept in the path ex. https://gitlab.com/group/subgroup
+func parseGitOrgURL(gitURL string) (baseURL string, owner string, err error) {
+	u, err := url.Parse(gitURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	paths := strings.Split(strings.Trim(u.Path, "/"), "/")
+	baseURL = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+
+	if len(paths) > 1 && (paths[0] == githubOrgsPath || paths[0] == gitlabGroupsPath) {
+		paths = paths[1:]
+	}
+
+	if paths[0] == "" {
+		return "", "", fmt.Errorf("organization url %s must contain organization name", gitURL)
+	}
+
+	return baseURL, strings.Join(paths, "/"), nil
+}
+
+// parseSourceURL splits project url of the source into base url, owner and project.
+// Project is empty when webhook is registered on organization.
+func parseSourceURL(source *v1alpha1.GitHook) (baseURL string, owner string, project string, err error) {
+	if source.Spec.Scope == v1alpha1.ScopeOrganization {
+		baseURL, owner, err = parseGitOrgURL(source.Spec.ProjectURL)
+		return baseURL, owner, "", err
+	}
+
+	return parseGitURL(source.Spec.GitProvider, source.Spec.ProjectURL)
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"testing"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+)
+
+func TestParseGitURL(t *testing.T) {
+	tests := []struct {
+		provider        v1alpha1.GitProvider
+		gitURL          string
+		expectedBaseURL string
+		expectedOwner   string
+		expectedProject string
+	}{
+		{
+			provider:        v1alpha1.Github,
+			gitURL:          "https://github.com/pongsatt/githook",
+			expectedBaseURL: "https://github.com",
+			expectedOwner:   "pongsatt",
+			expectedProject: "githook",
+		},
+		{
+			provider:        v1alpha1.Gogs,
+			gitURL:          "http://gogs.example.com:3000/pongsatt/githook.git",
+			expectedBaseURL: "http://gogs.example.com:3000",
+			expectedOwner:   "pongsatt",
+			expectedProject: "githook",
+		},
+		{
+			provider:        v1alpha1.AzureDevOps,
+			gitURL:          "https://dev.azure.com/myorg/My%20Project/_git/githook",
+			expectedBaseURL: "https://dev.azure.com/myorg",
+			expectedOwner:   "My Project",
+			expectedProject: "githook",
+		},
+		{
+			provider:        v1alpha1.AzureDevOps,
+			gitURL:          "https://myorg.visualstudio.com/myproject/_git/githook",
+			expectedBaseURL: "https://myorg.visualstudio.com",
+			expectedOwner:   "myproject",
+			expectedProject: "githook",
+		},
+		{
+			provider:        v1alpha1.BitbucketServer,
+			gitURL:          "https://bitbucket.example.com/projects/APP/repos/githook/browse",
+			expectedBaseURL: "https://bitbucket.example.com",
+			expectedOwner:   "APP",
+			expectedProject: "githook",
+		},
+		{
+			provider:        v1alpha1.BitbucketServer,
+			gitURL:          "https://example.com/bitbucket/projects/APP/repos/githook",
+			expectedBaseURL: "https://example.com/bitbucket",
+			expectedOwner:   "APP",
+			expectedProject: "githook",
+		},
+		{
+			// paths of other providers are not taken as azure devops or bitbucket server urls
+			provider:        v1alpha1.Gitlab,
+			gitURL:          "https://gitlab.example.com/team/_git/githook",
+			expectedBaseURL: "https://gitlab.example.com",
+			expectedOwner:   "team",
+			expectedProject: "_git",
+		},
+		{
+			provider:        v1alpha1.Github,
+			gitURL:          "https://github.com/projects/APP/repos/githook",
+			expectedBaseURL: "https://github.com",
+			expectedOwner:   "projects",
+			expectedProject: "APP",
+		},
+		{
+			provider:        v1alpha1.Gitea,
+			gitURL:          "https://gitea.example.com/myorg/githook/src/branch/projects/APP/repos/x",
+			expectedBaseURL: "https://gitea.example.com",
+			expectedOwner:   "myorg",
+			expectedProject: "githook",
+		},
+	}
+
+	for _, test := range tests {
+		baseURL, owner, project, err := parseGitURL(test.provider, test.gitURL)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if baseURL != test.expectedBaseURL || owner != test.expectedOwner || project != test.expectedProject {
+			t.Fatalf("expected %s %s %s but got %s %s %s", test.expectedBaseURL, test.expectedOwner, test.expectedProject, baseURL, owner, project)
+		}
+	}
+}
+
+func TestParseGitURLInvalid(t *testing.T) {
+	tests := []struct {
+		provider v1alpha1.GitProvider
+		gitURL   string
+	}{
+		{provider: v1alpha1.Github, gitURL: "https://github.com/pongsatt"},
+		{provider: v1alpha1.Github, gitURL: "https://github.com"},
+		{provider: v1alpha1.AzureDevOps, gitURL: "https://dev.azure.com/myorg/myproject"},
+		{provider: v1alpha1.BitbucketServer, gitURL: "https://bitbucket.example.com/scm/APP/githook.git"},
+	}
+
+	for _, test := range tests {
+		if _, _, _, err := parseGitURL(test.provider, test.gitURL); err == nil {
+			t.Fatalf("expected error for %s url %s", test.provider, test.gitURL)
+		}
+	}
+}
+
+func TestParseGitOrgURL(t *testing.T) {
+	tests := []struct {
+		gitURL          string
+		expectedBaseURL string
+		expectedOwner   string
+	}{
+		{gitURL: "https://github.com/myorg", expectedBaseURL: "https://github.com", expectedOwner: "myorg"},
+		{gitURL: "https://github.com/orgs/myorg/", expectedBaseURL: "https://github.com", expectedOwner: "myorg"},
+		{gitURL: "https://gitlab.com/groups/mygroup", expectedBaseURL: "https://gitlab.com", expectedOwner: "mygroup"},
+		{gitURL: "https://gitlab.example.com/mygroup/subgroup", expectedBaseURL: "https://gitlab.example.com", expectedOwner: "mygroup/subgroup"},
+	}
+
+	for _, test := range tests {
+		baseURL, owner, err := parseGitOrgURL(test.gitURL)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if baseURL != test.expectedBaseURL || owner != test.expectedOwner {
+			t.Fatalf("expected %s %s but got %s %s", test.expectedBaseURL, test.expectedOwner, baseURL, owner)
+		}
+	}
+
+	if _, _, err := parseGitOrgURL("https://github.com/"); err == nil {
+		t.Fatal("expected error for url without organization")
+	}
+}
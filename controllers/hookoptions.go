@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+// secretError reports failures to read a value from secret
+type secretError struct {
+	error
+}
+
+// buildHookFromSource builds hook options of GitHook with credentials read from secrets and configmaps.
+// Failures to read credentials are recorded as events of the GitHook.
+func buildHookFromSource(c client.Reader, recorder record.EventRecorder, source *v1alpha1.GitHook) (*model.HookOptions, error) {
+	hookOptions := &model.HookOptions{}
+
+	baseURL, owner, projectName, err := parseSourceURL(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process project url to get the project name: " + err.Error())
+	}
+
+	hookOptions.BaseURL = baseURL
+	hookOptions.Project = projectName
+	hookOptions.Owner = owner
+	hookOptions.Organization = source.Spec.Scope == v1alpha1.ScopeOrganization
+	hookOptions.ID = source.Status.ID
+
+	if githubApp := source.Spec.GithubApp; githubApp != nil {
+		privateKey, err := secretFrom(c, source.Namespace, githubApp.PrivateKey.SecretKeyRef)
+
+		if err != nil {
+			err = fmt.Errorf("failed to get github app private key from secret %s/%s: %s", source.Namespace, secretKeyRefName(githubApp.PrivateKey.SecretKeyRef), err)
+			recorder.Event(source, corev1.EventTypeWarning, reasonSecretNotFound, err.Error())
+			return nil, secretError{err}
+		}
+
+		hookOptions.GithubApp = &model.GithubAppOptions{
+			AppID:          githubApp.AppID,
+			InstallationID: githubApp.InstallationID,
+			PrivateKey:     privateKey,
+		}
+	} else {
+		hookOptions.AccessToken, err = secretFrom(c, source.Namespace, source.Spec.AccessToken.SecretKeyRef)
+
+		if err != nil {
+			err = fmt.Errorf("failed to get accesstoken from secret %s/%s: %s", source.Namespace, secretKeyRefName(source.Spec.AccessToken.SecretKeyRef), err)
+			recorder.Event(source, corev1.EventTypeWarning, reasonSecretNotFound, err.Error())
+			return nil, secretError{err}
+		}
+	}
+
+	hookOptions.SecretToken, err = secretFrom(c, source.Namespace, source.Spec.SecretToken.SecretKeyRef)
+
+	if err != nil {
+		err = fmt.Errorf("failed to get secret token from secret %s/%s: %s", source.Namespace, secretKeyRefName(source.Spec.SecretToken.SecretKeyRef), err)
+		recorder.Event(source, corev1.EventTypeWarning, reasonSecretNotFound, err.Error())
+		return nil, secretError{err}
+	}
+
+	if source.Spec.CABundle != nil {
+		hookOptions.CABundle, err = caBundleFrom(c, source.Namespace, source.Spec.CABundle)
+
+		if err != nil {
+			err = fmt.Errorf("failed to get CA bundle: %s", err)
+			recorder.Event(source, corev1.EventTypeWarning, reasonSecretNotFound, err.Error())
+			return nil, secretError{err}
+		}
+	}
+
+	return hookOptions, nil
+}
+
+// secretKeyRefName returns name of the secret referenced by secretKeySelector, empty when not set
+func secretKeyRefName(secretKeySelector *corev1.SecretKeySelector) string {
+	if secretKeySelector == nil {
+		return ""
+	}
+
+	return secretKeySelector.Name
+}
+
+func secretFrom(c client.Reader, namespace string, secretKeySelector *corev1.SecretKeySelector) (string, error) {
+	if secretKeySelector == nil {
+		return "", fmt.Errorf("secretKeyRef is required")
+	}
+
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: secretKeySelector.Name}, secret)
+
+	if err != nil {
+		return "", err
+	}
+	secretVal, ok := secret.Data[secretKeySelector.Key]
+	if !ok {
+		return "", fmt.Errorf(`key "%s" not found in secret "%s/%s"`, secretKeySelector.Key, namespace, secretKeySelector.Name)
+	}
+
+	return string(secretVal), nil
+}
+
+func configMapValueFrom(c client.Reader, namespace string, configMapKeySelector *corev1.ConfigMapKeySelector) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: configMapKeySelector.Name}, configMap)
+
+	if err != nil {
+		return "", err
+	}
+	value, ok := configMap.Data[configMapKeySelector.Key]
+	if !ok {
+		return "", fmt.Errorf(`key "%s" not found in configmap "%s"`, configMapKeySelector.Key, configMapKeySelector.Name)
+	}
+
+	return value, nil
+}
+
+// caBundleFrom reads CA bundle from either configmap or secret
+func caBundleFrom(c client.Reader, namespace string, source *v1alpha1.CABundleSource) (string, error) {
+	if source.ConfigMapKeyRef != nil {
+		return configMapValueFrom(c, namespace, source.ConfigMapKeyRef)
+	}
+
+	if source.SecretKeyRef != nil {
+		return secretFrom(c, namespace, source.SecretKeyRef)
+	}
+
+	return "", fmt.Errorf("either configMapKeyRef or secretKeyRef is required")
+}
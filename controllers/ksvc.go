@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"time"
+
+	servinv1alpha1 "github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	minReadyBackoff = 1 * time.Second
+	maxReadyBackoff = 60 * time.Second
+)
+
+// isKnativeServiceReady checks if knative service route is ready and has an address
+func isKnativeServiceReady(ksvc *servinv1alpha1.Service) bool {
+	routeCondition := ksvc.Status.GetCondition(servinv1alpha1.ServiceConditionRoutesReady)
+
+	return routeCondition != nil && routeCondition.Status == corev1.ConditionTrue && ksvc.Status.Address != nil
+}
+
+// readyBackoff returns how long to wait before checking knative service readiness again.
+// The delay grows with the time since the service last changed, its creation or the last
+// transition of its ready condition, so it doubles between checks, bounded by
+// minReadyBackoff and maxReadyBackoff.
+func readyBackoff(ksvc *servinv1alpha1.Service, now time.Time) time.Duration {
+	// status of the previous generation does not tell when the update was rolled out
+	if ksvc.Status.ObservedGeneration != ksvc.Generation {
+		return minReadyBackoff
+	}
+
+	changed := ksvc.CreationTimestamp.Time
+	if ready := ksvc.Status.GetCondition(servinv1alpha1.ServiceConditionReady); ready != nil && ready.LastTransitionTime.Inner.After(changed) {
+		changed = ready.LastTransitionTime.Inner.Time
+	}
+
+	if changed.IsZero() {
+		return minReadyBackoff
+	}
+
+	backoff := now.Sub(changed)
+
+	if backoff < minReadyBackoff {
+		return minReadyBackoff
+	}
+
+	if backoff > maxReadyBackoff {
+		return maxReadyBackoff
+	}
+
+	return backoff
+}
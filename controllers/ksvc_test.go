@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/knative/pkg/apis"
+	duckv1beta1 "github.com/knative/pkg/apis/duck/v1beta1"
+	servinv1alpha1 "github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadyBackoff(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		age             time.Duration
+		readySince      time.Duration
+		stale           bool
+		expectedBackoff time.Duration
+	}{
+		{
+			age:             0,
+			expectedBackoff: minReadyBackoff,
+		},
+		{
+			age:             4 * time.Second,
+			expectedBackoff: 4 * time.Second,
+		},
+		{
+			age:             10 * time.Minute,
+			expectedBackoff: maxReadyBackoff,
+		},
+		{
+			// updated service backs off from its last transition
+			age:             10 * time.Minute,
+			readySince:      4 * time.Second,
+			expectedBackoff: 4 * time.Second,
+		},
+		{
+			// update not observed by knative yet
+			age:             10 * time.Minute,
+			readySince:      10 * time.Minute,
+			stale:           true,
+			expectedBackoff: minReadyBackoff,
+		},
+	}
+
+	for _, test := range tests {
+		ksvc := &servinv1alpha1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.NewTime(now.Add(-test.age)),
+				Generation:        2,
+			},
+		}
+		ksvc.Status.ObservedGeneration = 2
+
+		if test.readySince > 0 {
+			ksvc.Status.Conditions = duckv1beta1.Conditions{{
+				Type:               servinv1alpha1.ServiceConditionReady,
+				Status:             corev1.ConditionUnknown,
+				LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(now.Add(-test.readySince))},
+			}}
+		}
+
+		if test.stale {
+			ksvc.Status.ObservedGeneration = 1
+		}
+
+		backoff := readyBackoff(ksvc, now)
+
+		if backoff != test.expectedBackoff {
+			t.Fatalf("expected backoff %s but got %s", test.expectedBackoff, backoff)
+		}
+	}
+}
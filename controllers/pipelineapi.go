@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"fmt"
+
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+)
+
+// pipelineRunReasonCancelled is the reason of the succeeded condition of pipelineruns cancelled
+// by tekton releases serving tekton.dev/v1beta1 and tekton.dev/v1
+const pipelineRunReasonCancelled = "Cancelled"
+
+// isTypedPipelineAPI checks if pipelineruns of api version are read as tekton.dev/v1alpha1 pipelineruns.
+// Pipelineruns of other api versions are read as unstructured objects.
+func isTypedPipelineAPI(apiVersion v1alpha1.PipelineAPIVersion) bool {
+	return apiVersion == "" || apiVersion == v1alpha1.PipelineV1alpha1
+}
+
+// pipelineRunKind returns the group version kind of pipelineruns of api version
+func pipelineRunKind(apiVersion v1alpha1.PipelineAPIVersion) schema.GroupVersionKind {
+	if isTypedPipelineAPI(apiVersion) {
+		return tektonv1alpha1.SchemeGroupVersion.WithKind("PipelineRun")
+	}
+
+	return schema.FromAPIVersionAndKind(string(apiVersion), "PipelineRun")
+}
+
+// newPipelineRunObject returns an empty pipelinerun of api version to read into
+func newPipelineRunObject(apiVersion v1alpha1.PipelineAPIVersion) runtime.Object {
+	if isTypedPipelineAPI(apiVersion) {
+		return &tektonv1alpha1.PipelineRun{}
+	}
+
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(pipelineRunKind(apiVersion))
+
+	return object
+}
+
+// newPipelineRunListObject returns an empty pipelinerun list of api version to list into
+func newPipelineRunListObject(apiVersion v1alpha1.PipelineAPIVersion) runtime.Object {
+	if isTypedPipelineAPI(apiVersion) {
+		return &tektonv1alpha1.PipelineRunList{}
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(pipelineRunKind(apiVersion).GroupVersion().WithKind("PipelineRunList"))
+
+	return list
+}
+
+// pipelineRunView returns pipelinerun of any api version as tekton.dev/v1alpha1 pipelinerun.
+// Specs differ between api versions so only metadata, conditions and times of status are kept.
+func pipelineRunView(object runtime.Object) (*tektonv1alpha1.PipelineRun, error) {
+	switch pipelineRun := object.(type) {
+	case *tektonv1alpha1.PipelineRun:
+		return pipelineRun, nil
+	case *unstructured.Unstructured:
+		status := map[string]interface{}{}
+		for _, field := range []string{"conditions", "startTime", "completionTime"} {
+			if value, found, _ := unstructured.NestedFieldNoCopy(pipelineRun.Object, "status", field); found {
+				status[field] = value
+			}
+		}
+
+		view := &tektonv1alpha1.PipelineRun{}
+		err := runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]interface{}{
+			"metadata": pipelineRun.Object["metadata"],
+			"status":   status,
+		}, view)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipelinerun %s: %s", pipelineRun.GetName(), err)
+		}
+
+		return view, nil
+	}
+
+	return nil, fmt.Errorf("unexpected pipelinerun type %T", object)
+}
+
+// pipelineRunViews returns pipelineruns of list of any api version as tekton.dev/v1alpha1 pipelineruns
+func pipelineRunViews(list runtime.Object) ([]tektonv1alpha1.PipelineRun, error) {
+	switch list := list.(type) {
+	case *tektonv1alpha1.PipelineRunList:
+		return list.Items, nil
+	case *unstructured.UnstructuredList:
+		views := []tektonv1alpha1.PipelineRun{}
+		for i := range list.Items {
+			view, err := pipelineRunView(&list.Items[i])
+			if err != nil {
+				return nil, err
+			}
+			views = append(views, *view)
+		}
+
+		return views, nil
+	}
+
+	return nil, fmt.Errorf("unexpected pipelinerun list type %T", list)
+}
@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	githookclient "gitlab.com/pongsatt/githook/pkg/client"
+	"gitlab.com/pongsatt/githook/pkg/githook"
+	"gitlab.com/pongsatt/githook/pkg/model"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+const (
+	// annotationReportedState keeps the last state reported on the commit so it is reported once
+	annotationReportedState = "githook.tools.pongzt.com/reported-state"
+
+	// annotationCheckRunID keeps the id of github check run reporting the pipelinerun
+	annotationCheckRunID = "githook.tools.pongzt.com/check-run-id"
+
+	// annotationResourcesOwned marks pipelinerun as owner of its git pipelineresource and workspace claims
+	annotationResourcesOwned = "githook.tools.pongzt.com/resources-owned"
+
+	// maxStatusDescription is the number of characters git providers accept as status description
+	maxStatusDescription = 140
+)
+
+// reasons of events recorded on GitHook by status reporting
+const (
+	reasonStatusFailed       = "StatusReportFailed"
+	reasonStatusNotSupported = "StatusReportNotSupported"
+)
+
+// PipelineRunReconciler reports results of pipelineruns created by GitHooks as commit statuses
+// and starts pipelineruns queued until they finish
+type PipelineRunReconciler struct {
+	client.Client
+	Tekton   *tekton.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+
+	// APIVersion is the tekton api version of pipelineruns reconciled, tekton.dev/v1alpha1 when empty
+	APIVersion v1alpha1.PipelineAPIVersion
+
+	// GithubAppTokens caches installation tokens of github apps
+	GithubAppTokens *githookclient.GithubAppTokenCache
+}
+
+// pipelineRunState maps the succeeded condition of pipelinerun to commit state and description
+func pipelineRunState(pipelineRun *tektonv1alpha1.PipelineRun) (model.CommitState, string) {
+	condition := pipelineRun.Status.GetCondition(apis.ConditionSucceeded)
+
+	switch {
+	case condition == nil && pipelineRun.Status.StartTime == nil:
+		return model.CommitStatePending, fmt.Sprintf("Pipeline run %s is pending", pipelineRun.Name)
+	case condition == nil || condition.Status == corev1.ConditionUnknown:
+		return model.CommitStateRunning, fmt.Sprintf("Pipeline run %s is running", pipelineRun.Name)
+	case condition.Status == corev1.ConditionTrue:
+		return model.CommitStateSuccess, fmt.Sprintf("Pipeline run %s succeeded", pipelineRun.Name)
+	case condition.Reason == tektonv1alpha1.PipelineRunSpecStatusCancelled || condition.Reason == pipelineRunReasonCancelled:
+		return model.CommitStateCancelled, fmt.Sprintf("Pipeline run %s was cancelled", pipelineRun.Name)
+	}
+
+	description := fmt.Sprintf("Pipeline run %s failed", pipelineRun.Name)
+	if condition.Message != "" {
+		description = fmt.Sprintf("%s: %s", description, condition.Message)
+	}
+
+	return model.CommitStateFailure, description
+}
+
+// statusTargetURL replaces $NAMESPACE and $NAME of target url with those of pipelinerun
+func statusTargetURL(targetURL string, pipelineRun *tektonv1alpha1.PipelineRun) string {
+	return strings.NewReplacer("$NAMESPACE", pipelineRun.Namespace, "$NAME", pipelineRun.Name).Replace(targetURL)
+}
+
+// truncateDescription truncates description to maxStatusDescription characters on a rune boundary
+func truncateDescription(description string) string {
+	runes := []rune(description)
+	if len(runes) <= maxStatusDescription {
+		return description
+	}
+
+	return string(runes[:maxStatusDescription-3]) + "..."
+}
+
+// isGitHookRun checks if pipelinerun is created by GitHook
+func isGitHookRun(annotations map[string]string) bool {
+	return annotations[tekton.AnnotationGitHookName] != ""
+}
+
+// isReportable checks if pipelinerun is created by GitHook for a commit
+func isReportable(annotations map[string]string) bool {
+	return annotations[tekton.AnnotationGitHookName] != "" && annotations[tekton.AnnotationCommit] != ""
+}
+
+// +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=tekton.dev,resources=pipelineresources,verbs=get;list;watch;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;patch;delete
+
+// Reconcile makes pipelinerun owner of resources created for it, starts pipelinerun queued until
+// pipelinerun finishes and reports the state of pipelinerun on its commit when it changes
+func (r *PipelineRunReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithName(req.NamespacedName.String())
+	ctx := context.Background()
+
+	object := newPipelineRunObject(r.APIVersion)
+	if err := r.Get(ctx, req.NamespacedName, object); err != nil {
+		return ctrl.Result{}, ignoreNotFound(err)
+	}
+
+	pipelineRun, err := pipelineRunView(object)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ownResources(ctx, object); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if pipelineRun.IsDone() && r.Tekton != nil {
+		queued, err := r.Tekton.StartQueued(r.APIVersion, pipelineRun)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if queued != nil {
+			log.Info("start queued pipelinerun", "name", queued.GetName())
+		}
+	}
+
+	if !isReportable(pipelineRun.Annotations) {
+		return ctrl.Result{}, nil
+	}
+
+	state, description := pipelineRunState(pipelineRun)
+
+	if pipelineRun.Annotations[annotationReportedState] == string(state) {
+		return ctrl.Result{}, nil
+	}
+
+	source := &v1alpha1.GitHook{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: pipelineRun.Namespace, Name: pipelineRun.Annotations[tekton.AnnotationGitHookName]}, source)
+	if err != nil {
+		return ctrl.Result{}, ignoreNotFound(err)
+	}
+
+	if source.Spec.CommitStatus == nil {
+		return ctrl.Result{}, nil
+	}
+
+	status := &model.CommitStatus{
+		SHA:         pipelineRun.Annotations[tekton.AnnotationCommit],
+		Branch:      pipelineRun.Annotations[tekton.AnnotationBranch],
+		State:       state,
+		Context:     source.Spec.CommitStatus.Context,
+		Description: truncateDescription(description),
+		TargetURL:   statusTargetURL(source.Spec.CommitStatus.TargetURL, pipelineRun),
+		CheckRunID:  pipelineRun.Annotations[annotationCheckRunID],
+	}
+
+	if status.Context == "" {
+		status.Context = "githook/" + source.Name
+	}
+
+	// pipelineruns of every trigger matching the event report their own status
+	if trigger := pipelineRun.Annotations[tekton.AnnotationTrigger]; trigger != "" {
+		status.Context += "/" + trigger
+	}
+
+	log.Info("report pipelinerun status", "state", state, "commit", status.SHA)
+	checkRunID, err := r.report(source, pipelineRun, status)
+
+	if err == model.ErrNotSupported {
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, reasonStatusNotSupported, "Commit status is not supported by git provider %s", source.Spec.GitProvider)
+	} else if err != nil {
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, reasonStatusFailed, "Failed to report status of pipelinerun %s: %s", pipelineRun.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	// only reported annotations are patched so changes made meanwhile to pipelinerun are kept
+	patch := client.MergeFrom(object.DeepCopyObject())
+
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	annotations := accessor.GetAnnotations()
+	annotations[annotationReportedState] = string(state)
+	if checkRunID != "" {
+		annotations[annotationCheckRunID] = checkRunID
+	}
+	accessor.SetAnnotations(annotations)
+
+	return ctrl.Result{}, r.Patch(ctx, object, patch)
+}
+
+// ownResources makes pipelinerun owner of its git pipelineresource and workspace claims
+// unless it is annotated as their owner already
+func (r *PipelineRunReconciler) ownResources(ctx context.Context, object runtime.Object) error {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+
+	annotations := accessor.GetAnnotations()
+	if r.Tekton == nil || annotations[annotationResourcesOwned] == "true" ||
+		(annotations[tekton.AnnotationGitResource] == "" && annotations[tekton.AnnotationWorkspaceClaims] == "") {
+		return nil
+	}
+
+	if err := r.Tekton.OwnResources(accessor); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(object.DeepCopyObject())
+	annotations[annotationResourcesOwned] = "true"
+	accessor.SetAnnotations(annotations)
+
+	return r.Patch(ctx, object, patch)
+}
+
+// report sets commit status or check run on the repository of the pipelinerun
+func (r *PipelineRunReconciler) report(source *v1alpha1.GitHook, pipelineRun *tektonv1alpha1.PipelineRun, status *model.CommitStatus) (string, error) {
+	hookOptions, err := buildHookFromSource(r.Client, r.Recorder, source)
+	if err != nil {
+		return "", err
+	}
+
+	// organization webhooks trigger pipelineruns of many repositories
+	if owner, name := pipelineRun.Annotations[tekton.AnnotationRepoOwner], pipelineRun.Annotations[tekton.AnnotationRepoName]; name != "" {
+		hookOptions.Owner = owner
+		hookOptions.Project = name
+	}
+
+	gitClient, err := githook.NewForProvider(source.Spec.GitProvider, hookOptions, r.GithubAppTokens)
+	if err != nil {
+		return "", err
+	}
+
+	if source.Spec.CommitStatus.CheckRun {
+		return gitClient.SetCheckRun(hookOptions, status)
+	}
+
+	return "", gitClient.SetCommitStatus(hookOptions, status)
+}
+
+// SetupWithManager setups controller of pipelineruns of api version with manager
+func (r *PipelineRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// controllers of every api version need their own name
+	name := "pipelinerun"
+	if !isTypedPipelineAPI(r.APIVersion) {
+		name += "-" + pipelineRunKind(r.APIVersion).Version
+	}
+
+	c, err := controller.New(name, mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: newPipelineRunObject(r.APIVersion)}, &handler.EnqueueRequestForObject{},
+		predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return isGitHookRun(e.Meta.GetAnnotations())
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return isGitHookRun(e.MetaNew.GetAnnotations())
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/model"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+func pipelineRunWithCondition(condition *apis.Condition) *tektonv1alpha1.PipelineRun {
+	pipelineRun := &tektonv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "build-x7k2p"},
+	}
+
+	if condition != nil {
+		now := metav1.Now()
+		pipelineRun.Status.StartTime = &now
+		pipelineRun.Status.SetCondition(condition)
+	}
+
+	return pipelineRun
+}
+
+func TestPipelineRunState(t *testing.T) {
+	tests := []struct {
+		condition   *apis.Condition
+		state       model.CommitState
+		description string
+	}{
+		{condition: nil, state: model.CommitStatePending},
+		{condition: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionUnknown, Reason: "Running"}, state: model.CommitStateRunning},
+		{condition: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}, state: model.CommitStateSuccess},
+		{condition: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "PipelineRunCancelled"}, state: model.CommitStateCancelled},
+		{condition: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Cancelled"}, state: model.CommitStateCancelled},
+		{condition: &apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Failed", Message: "task build failed"}, state: model.CommitStateFailure, description: "task build failed"},
+	}
+
+	for i, test := range tests {
+		state, description := pipelineRunState(pipelineRunWithCondition(test.condition))
+
+		if state != test.state {
+			t.Fatalf("case %d: expected state %s but got %s", i, test.state, state)
+		}
+
+		if !strings.Contains(description, test.description) {
+			t.Fatalf("case %d: expected description containing %q but got %q", i, test.description, description)
+		}
+	}
+}
+
+func TestStatusTargetURL(t *testing.T) {
+	targetURL := statusTargetURL("https://tekton.example.com/#/namespaces/$NAMESPACE/pipelineruns/$NAME", pipelineRunWithCondition(nil))
+
+	if targetURL != "https://tekton.example.com/#/namespaces/ci/pipelineruns/build-x7k2p" {
+		t.Fatalf("unexpected target url %s", targetURL)
+	}
+}
+
+func TestTruncateDescription(t *testing.T) {
+	if description := truncateDescription(strings.Repeat("x", 200)); len(description) != maxStatusDescription {
+		t.Fatalf("expected description of %d characters but got %d", maxStatusDescription, len(description))
+	}
+
+	description := truncateDescription(strings.Repeat("ü", 200))
+	if !utf8.ValidString(description) || utf8.RuneCountInString(description) != maxStatusDescription {
+		t.Fatalf("expected valid description of %d characters but got %q", maxStatusDescription, description)
+	}
+
+	if description := truncateDescription("short"); description != "short" {
+		t.Fatalf("expected description not to be truncated but got %s", description)
+	}
+}
+
+func TestPipelineRunView(t *testing.T) {
+	object := newPipelineRunObject(v1alpha1.PipelineV1).(*unstructured.Unstructured)
+	object.Object["metadata"] = map[string]interface{}{
+		"namespace":   "ci",
+		"name":        "build-x7k2p",
+		"annotations": map[string]interface{}{tekton.AnnotationCommit: "9b1c4e2"},
+	}
+	object.Object["spec"] = map[string]interface{}{
+		"pipelineRef":     map[string]interface{}{"name": "build"},
+		"taskRunTemplate": map[string]interface{}{"serviceAccountName": "builder"},
+	}
+	object.Object["status"] = map[string]interface{}{
+		"startTime":      "2019-07-01T10:00:00Z",
+		"completionTime": "2019-07-01T10:05:00Z",
+		"childReferences": []interface{}{
+			map[string]interface{}{"kind": "TaskRun", "name": "build-x7k2p-compile"},
+		},
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":               "Succeeded",
+				"status":             "False",
+				"reason":             "Cancelled",
+				"lastTransitionTime": "2019-07-01T10:05:00Z",
+			},
+		},
+	}
+
+	pipelineRun, err := pipelineRunView(object)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pipelineRun.Name != "build-x7k2p" || pipelineRun.Annotations[tekton.AnnotationCommit] != "9b1c4e2" {
+		t.Fatalf("expected metadata of pipelinerun but got %v", pipelineRun.ObjectMeta)
+	}
+
+	if state, _ := pipelineRunState(pipelineRun); state != model.CommitStateCancelled || !pipelineRun.IsDone() {
+		t.Fatalf("expected cancelled pipelinerun but got %s", state)
+	}
+
+	if finished := finishedTime(pipelineRun); !finished.Equal(time.Date(2019, 7, 1, 10, 5, 0, 0, time.UTC)) {
+		t.Fatalf("expected completion time of pipelinerun but got %s", finished)
+	}
+
+	if typed, _ := pipelineRunView(newPipelineRunObject("")); typed == nil {
+		t.Fatal("expected tekton.dev/v1alpha1 pipelinerun to be its own view")
+	}
+}
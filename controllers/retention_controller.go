@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+// retentionInterval is how often retention policy of GitHook is enforced
+const retentionInterval = 10 * time.Minute
+
+// reasons of events recorded on GitHook by retention policy
+const (
+	reasonRetentionDeleted = "RetentionDeleted"
+	reasonRetentionFailed  = "RetentionFailed"
+)
+
+// RetentionReconciler periodically deletes old pipelineruns of GitHooks with retention policy
+type RetentionReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+}
+
+// finishedTime returns the time pipelinerun completed or was created when unknown
+func finishedTime(pipelineRun *tektonv1alpha1.PipelineRun) time.Time {
+	if pipelineRun.Status.CompletionTime != nil {
+		return pipelineRun.Status.CompletionTime.Time
+	}
+
+	return pipelineRun.CreationTimestamp.Time
+}
+
+// expiredPipelineRuns returns finished pipelineruns exceeding the limits of retention policy
+func expiredPipelineRuns(retention *v1alpha1.RetentionPolicy, pipelineRuns []tektonv1alpha1.PipelineRun, now time.Time) []tektonv1alpha1.PipelineRun {
+	finished := []tektonv1alpha1.PipelineRun{}
+	for _, pipelineRun := range pipelineRuns {
+		if pipelineRun.IsDone() {
+			finished = append(finished, pipelineRun)
+		}
+	}
+
+	// latest first
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finishedTime(&finished[i]).After(finishedTime(&finished[j]))
+	})
+
+	expired := []tektonv1alpha1.PipelineRun{}
+	succeeded, failed := 0, 0
+
+	for _, pipelineRun := range finished {
+		limit := retention.FailedRunsLimit
+		count := &failed
+
+		if pipelineRun.Status.GetCondition(apis.ConditionSucceeded).IsTrue() {
+			limit = retention.SuccessfulRunsLimit
+			count = &succeeded
+		}
+		*count++
+
+		switch {
+		case limit != nil && int32(*count) > *limit:
+			expired = append(expired, pipelineRun)
+		case retention.MaxAge != nil && now.Sub(finishedTime(&pipelineRun)) > retention.MaxAge.Duration:
+			expired = append(expired, pipelineRun)
+		}
+	}
+
+	return expired
+}
+
+// unusedGitResources returns git pipelineresources created for GitHook which are neither owned
+// nor referred to by a pipelinerun. Resources created before they were owned by their pipelinerun
+// are only known by name.
+func unusedGitResources(source *v1alpha1.GitHook, resources []tektonv1alpha1.PipelineResource, pipelineRuns []tektonv1alpha1.PipelineRun) []tektonv1alpha1.PipelineResource {
+	used := map[string]bool{}
+	for _, pipelineRun := range pipelineRuns {
+		for _, binding := range pipelineRun.Spec.Resources {
+			used[binding.ResourceRef.Name] = true
+		}
+	}
+
+	unused := []tektonv1alpha1.PipelineResource{}
+	for _, resource := range resources {
+		if resource.Spec.Type != tektonv1alpha1.PipelineResourceTypeGit || used[resource.Name] || len(resource.OwnerReferences) > 0 {
+			continue
+		}
+
+		createdBy, annotated := resource.Annotations[tekton.AnnotationGitHookName]
+		if createdBy == source.Name || (!annotated && strings.HasPrefix(resource.Name, tekton.GitResourceGenerateName(source.Name))) {
+			unused = append(unused, resource)
+		}
+	}
+
+	return unused
+}
+
+// Reconcile deletes pipelineruns of GitHook exceeding its retention policy
+// and checks again after retention interval
+func (r *RetentionReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithName(req.NamespacedName.String())
+	ctx := context.Background()
+
+	source := &v1alpha1.GitHook{}
+	if err := r.Get(ctx, req.NamespacedName, source); err != nil {
+		return ctrl.Result{}, ignoreNotFound(err)
+	}
+
+	if source.Spec.Retention == nil || source.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	deleted, err := r.enforce(ctx, source)
+	if deleted > 0 {
+		log.Info("deleted pipelineruns exceeding retention policy", "count", deleted)
+		r.Recorder.Eventf(source, corev1.EventTypeNormal, reasonRetentionDeleted, "Deleted %d pipelineruns exceeding retention policy", deleted)
+	}
+
+	if err != nil {
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, reasonRetentionFailed, "Failed to enforce retention policy: %s", err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: retentionInterval}, nil
+}
+
+// enforce deletes expired pipelineruns of the pipeline api version of GitHook and, when enabled,
+// its unused git pipelineresources. It returns the number of pipelineruns deleted.
+func (r *RetentionReconciler) enforce(ctx context.Context, source *v1alpha1.GitHook) (int, error) {
+	apiVersion := source.Spec.PipelineAPIVersion
+
+	list := newPipelineRunListObject(apiVersion)
+	err := r.List(ctx, list, client.InNamespace(source.Namespace),
+		client.MatchingLabels(map[string]string{tekton.LabelGitHookName: tekton.SanitizeLabelValue(source.Name)}))
+	if err != nil {
+		return 0, err
+	}
+
+	views, err := pipelineRunViews(list)
+	if err != nil {
+		return 0, err
+	}
+
+	// labels may be truncated so the annotation tells the GitHook
+	pipelineRuns := []tektonv1alpha1.PipelineRun{}
+	for _, pipelineRun := range views {
+		if pipelineRun.Annotations[tekton.AnnotationGitHookName] == source.Name {
+			pipelineRuns = append(pipelineRuns, pipelineRun)
+		}
+	}
+
+	deleted := 0
+	expired := map[string]bool{}
+	for _, pipelineRun := range expiredPipelineRuns(source.Spec.Retention, pipelineRuns, time.Now()) {
+		object := newPipelineRunObject(apiVersion)
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			return deleted, err
+		}
+		accessor.SetNamespace(pipelineRun.Namespace)
+		accessor.SetName(pipelineRun.Name)
+
+		if err := r.Delete(ctx, object); ignoreNotFound(err) != nil {
+			return deleted, err
+		}
+		expired[pipelineRun.Name] = true
+		deleted++
+	}
+
+	// git pipelineresources are only created for tekton.dev/v1alpha1 pipelineruns
+	if !source.Spec.Retention.DeleteGitResources || !isTypedPipelineAPI(apiVersion) {
+		return deleted, nil
+	}
+
+	// git pipelineresources are shared by pipelineruns of the namespace
+	all := &tektonv1alpha1.PipelineRunList{}
+	if err := r.List(ctx, all, client.InNamespace(source.Namespace)); err != nil {
+		return deleted, err
+	}
+
+	remaining := []tektonv1alpha1.PipelineRun{}
+	for _, pipelineRun := range all.Items {
+		if !expired[pipelineRun.Name] {
+			remaining = append(remaining, pipelineRun)
+		}
+	}
+
+	resources := &tektonv1alpha1.PipelineResourceList{}
+	if err := r.List(ctx, resources, client.InNamespace(source.Namespace)); err != nil {
+		return deleted, err
+	}
+
+	for _, resource := range unusedGitResources(source, resources.Items, remaining) {
+		// keep resources created after listing pipelineruns which may be about to be used
+		if time.Since(resource.CreationTimestamp.Time) < retentionInterval {
+			continue
+		}
+
+		if err := r.Delete(ctx, &resource); ignoreNotFound(err) != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+// SetupWithManager setups controller with manager
+func (r *RetentionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("retention", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &v1alpha1.GitHook{}}, &handler.EnqueueRequestForObject{})
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/knative/pkg/apis"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+func finishedPipelineRun(name string, status corev1.ConditionStatus, completion time.Time) tektonv1alpha1.PipelineRun {
+	pipelineRun := tektonv1alpha1.PipelineRun{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	completionTime := metav1.NewTime(completion)
+	pipelineRun.Status.CompletionTime = &completionTime
+	pipelineRun.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: status})
+
+	return pipelineRun
+}
+
+func expiredNames(pipelineRuns []tektonv1alpha1.PipelineRun) map[string]bool {
+	names := map[string]bool{}
+	for _, pipelineRun := range pipelineRuns {
+		names[pipelineRun.Name] = true
+	}
+
+	return names
+}
+
+func TestExpiredPipelineRuns(t *testing.T) {
+	now := time.Now()
+	one := int32(1)
+
+	pipelineRuns := []tektonv1alpha1.PipelineRun{
+		finishedPipelineRun("success-old", corev1.ConditionTrue, now.Add(-3*time.Hour)),
+		finishedPipelineRun("success-new", corev1.ConditionTrue, now.Add(-time.Hour)),
+		finishedPipelineRun("failed-old", corev1.ConditionFalse, now.Add(-4*time.Hour)),
+		finishedPipelineRun("failed-new", corev1.ConditionFalse, now.Add(-2*time.Hour)),
+		finishedPipelineRun("running", corev1.ConditionUnknown, now.Add(-5*time.Hour)),
+	}
+
+	tests := []struct {
+		retention *v1alpha1.RetentionPolicy
+		expired   []string
+	}{
+		{retention: &v1alpha1.RetentionPolicy{}, expired: []string{}},
+		{retention: &v1alpha1.RetentionPolicy{SuccessfulRunsLimit: &one}, expired: []string{"success-old"}},
+		{retention: &v1alpha1.RetentionPolicy{SuccessfulRunsLimit: &one, FailedRunsLimit: &one}, expired: []string{"success-old", "failed-old"}},
+		{retention: &v1alpha1.RetentionPolicy{MaxAge: &metav1.Duration{Duration: 150 * time.Minute}}, expired: []string{"success-old", "failed-old"}},
+		{retention: &v1alpha1.RetentionPolicy{FailedRunsLimit: &one, MaxAge: &metav1.Duration{Duration: 90 * time.Minute}}, expired: []string{"success-old", "failed-old", "failed-new"}},
+	}
+
+	for i, test := range tests {
+		expired := expiredNames(expiredPipelineRuns(test.retention, pipelineRuns, now))
+
+		if len(expired) != len(test.expired) {
+			t.Fatalf("case %d: expected %v to be expired but got %v", i, test.expired, expired)
+		}
+
+		for _, name := range test.expired {
+			if !expired[name] {
+				t.Fatalf("case %d: expected %s to be expired but got %v", i, name, expired)
+			}
+		}
+	}
+}
+
+func TestUnusedGitResources(t *testing.T) {
+	source := &v1alpha1.GitHook{ObjectMeta: metav1.ObjectMeta{Name: "sample"}}
+
+	gitResource := func(name string, resourceType tektonv1alpha1.PipelineResourceType) tektonv1alpha1.PipelineResource {
+		return tektonv1alpha1.PipelineResource{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       tektonv1alpha1.PipelineResourceSpec{Type: resourceType},
+		}
+	}
+
+	annotated := gitResource("sample-git-source-orphan", tektonv1alpha1.PipelineResourceTypeGit)
+	annotated.Annotations = map[string]string{tekton.AnnotationGitHookName: "sample"}
+
+	owned := gitResource("sample-git-source-owned", tektonv1alpha1.PipelineResourceTypeGit)
+	owned.Annotations = map[string]string{tekton.AnnotationGitHookName: "sample"}
+	owned.OwnerReferences = []metav1.OwnerReference{{Kind: "PipelineRun", Name: "sample-x7k2p"}}
+
+	otherHook := gitResource("sample-git-source-other", tektonv1alpha1.PipelineResourceTypeGit)
+	otherHook.Annotations = map[string]string{tekton.AnnotationGitHookName: "sample-git"}
+
+	resources := []tektonv1alpha1.PipelineResource{
+		gitResource("sample-git-source-used", tektonv1alpha1.PipelineResourceTypeGit),
+		gitResource("sample-git-source-unused", tektonv1alpha1.PipelineResourceTypeGit),
+		gitResource("sample-git-source-image", tektonv1alpha1.PipelineResourceTypeImage),
+		gitResource("other-git-source-unused", tektonv1alpha1.PipelineResourceTypeGit),
+		annotated,
+		owned,
+		otherHook,
+	}
+
+	pipelineRun := tektonv1alpha1.PipelineRun{}
+	pipelineRun.Spec.Resources = []tektonv1alpha1.PipelineResourceBinding{
+		{Name: "git-source", ResourceRef: tektonv1alpha1.PipelineResourceRef{Name: "sample-git-source-used"}},
+	}
+
+	unused := unusedGitResources(source, resources, []tektonv1alpha1.PipelineRun{pipelineRun})
+
+	if len(unused) != 2 || unused[0].Name != "sample-git-source-unused" || unused[1].Name != "sample-git-source-orphan" {
+		t.Fatalf("expected sample-git-source-unused and sample-git-source-orphan to be unused but got %v", unused)
+	}
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+)
+
+// usesPipelineRunSpec checks if GitHook creates tekton.dev/v1beta1 or tekton.dev/v1
+// pipelineruns from PipelineRunSpec instead of RunSpec
+func usesPipelineRunSpec(source *v1alpha1.GitHook) bool {
+	apiVersion := source.Spec.PipelineAPIVersion
+
+	return apiVersion != "" && apiVersion != v1alpha1.PipelineV1alpha1
+}
+
+// runSpecJSONFrom returns the pipelinerun spec of GitHook in json format
+// for the pipeline api version of GitHook
+func runSpecJSONFrom(source *v1alpha1.GitHook) (string, error) {
+	if err := validateWorkspaces(source); err != nil {
+		return "", err
+	}
+
+	if err := validateTriggers(source); err != nil {
+		return "", err
+	}
+
+	if !usesPipelineRunSpec(source) {
+		runSpecJSON, err := json.Marshal(source.Spec.RunSpec)
+		if err != nil {
+			return "", err
+		}
+		return string(runSpecJSON), nil
+	}
+
+	apiVersion := source.Spec.PipelineAPIVersion
+
+	// pipelinerun spec of triggers are run instead
+	if len(source.Spec.Triggers) > 0 && (source.Spec.PipelineRunSpec == nil || len(source.Spec.PipelineRunSpec.Raw) == 0) {
+		return "{}", nil
+	}
+
+	if source.Spec.PipelineRunSpec == nil || len(source.Spec.PipelineRunSpec.Raw) == 0 {
+		return "", fmt.Errorf("pipelineRunSpec is required for pipeline api version %s", apiVersion)
+	}
+
+	for i, route := range source.Spec.Routes {
+		if route.PipelineRunSpec == nil {
+			return "", fmt.Errorf("route %d: pipelineRunSpec is required for pipeline api version %s", i, apiVersion)
+		}
+	}
+
+	return string(source.Spec.PipelineRunSpec.Raw), nil
+}
+
+// validateTriggers checks if every trigger has a unique name, event types registered
+// by GitHook and a pipelinerun spec of the pipeline api version of GitHook
+func validateTriggers(source *v1alpha1.GitHook) error {
+	registered := map[v1alpha1.GitEvent]bool{}
+	for _, eventType := range source.Spec.GetEventTypes() {
+		registered[eventType] = true
+	}
+
+	names := map[string]bool{}
+
+	for _, trigger := range source.Spec.Triggers {
+		if names[trigger.Name] {
+			return fmt.Errorf("trigger %s: name is not unique", trigger.Name)
+		}
+		names[trigger.Name] = true
+
+		for _, eventType := range trigger.EventTypes {
+			if !registered[eventType] {
+				return fmt.Errorf("trigger %s: event type %s is not one of eventTypes of GitHook", trigger.Name, eventType)
+			}
+		}
+
+		hasRunSpec := !equality.Semantic.DeepEqual(trigger.RunSpec, tektonv1alpha1.PipelineRunSpec{})
+		hasPipelineRunSpec := trigger.PipelineRunSpec != nil && len(trigger.PipelineRunSpec.Raw) > 0
+
+		if usesPipelineRunSpec(source) {
+			if hasRunSpec {
+				return fmt.Errorf("trigger %s: runspec is only supported by pipeline api version %s", trigger.Name, v1alpha1.PipelineV1alpha1)
+			}
+		} else if hasPipelineRunSpec {
+			return fmt.Errorf("trigger %s: pipelineRunSpec is only supported by pipeline api version %s and %s", trigger.Name, v1alpha1.PipelineV1beta1, v1alpha1.PipelineV1)
+		}
+
+		if trigger.PipelineRef == nil && !hasRunSpec && !hasPipelineRunSpec {
+			return fmt.Errorf("trigger %s: one of pipelineRef, runspec or pipelineRunSpec is required", trigger.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateWorkspaces checks if every workspace template has a single volume source
+// and the pipeline api version of GitHook supports workspaces
+func validateWorkspaces(source *v1alpha1.GitHook) error {
+	if len(source.Spec.Workspaces) > 0 && !usesPipelineRunSpec(source) {
+		return fmt.Errorf("workspaces are only supported by pipeline api version %s and %s", v1alpha1.PipelineV1beta1, v1alpha1.PipelineV1)
+	}
+
+	for _, workspace := range source.Spec.Workspaces {
+		sources := 0
+		if workspace.EmptyDir != nil {
+			sources++
+		}
+		if workspace.VolumeClaimTemplate != nil {
+			sources++
+		}
+		if workspace.PersistentVolumeClaim != nil {
+			sources++
+		}
+
+		if sources != 1 {
+			return fmt.Errorf("workspace %s: exactly one of emptyDir, volumeClaimTemplate or persistentVolumeClaim is required", workspace.Name)
+		}
+	}
+
+	return nil
+}
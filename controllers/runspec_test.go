@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+)
+
+func TestRunSpecJSONFrom(t *testing.T) {
+	pipelineRunSpec := &runtime.RawExtension{Raw: []byte(`{"pipelineRef":{"name":"build"},"workspaces":[{"name":"source","emptyDir":{}}]}`)}
+
+	tests := []struct {
+		spec     v1alpha1.GitHookSpec
+		expected string
+		err      string
+	}{
+		{
+			spec:     v1alpha1.GitHookSpec{RunSpec: tektonv1alpha1.PipelineRunSpec{PipelineRef: tektonv1alpha1.PipelineRef{Name: "build"}}},
+			expected: `"pipelineRef":{"name":"build"}`,
+		},
+		{
+			spec:     v1alpha1.GitHookSpec{PipelineAPIVersion: v1alpha1.PipelineV1, PipelineRunSpec: pipelineRunSpec},
+			expected: `"workspaces":[{"name":"source","emptyDir":{}}]`,
+		},
+		{
+			spec: v1alpha1.GitHookSpec{PipelineAPIVersion: v1alpha1.PipelineV1beta1},
+			err:  "pipelineRunSpec is required",
+		},
+		{
+			spec: v1alpha1.GitHookSpec{PipelineAPIVersion: v1alpha1.PipelineV1beta1, PipelineRunSpec: pipelineRunSpec, Routes: []v1alpha1.RepositoryRoute{{Repositories: []string{"app"}}}},
+			err:  "route 0: pipelineRunSpec is required",
+		},
+		{
+			spec:     v1alpha1.GitHookSpec{PipelineAPIVersion: v1alpha1.PipelineV1, PipelineRunSpec: pipelineRunSpec, ConcurrencyPolicy: v1alpha1.ConcurrencyQueue},
+			expected: `"pipelineRef"`,
+		},
+		{
+			spec: v1alpha1.GitHookSpec{Workspaces: []v1alpha1.WorkspaceTemplate{{Name: "source", EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			err:  "workspaces are only supported",
+		},
+		{
+			spec: v1alpha1.GitHookSpec{PipelineAPIVersion: v1alpha1.PipelineV1, PipelineRunSpec: pipelineRunSpec, Workspaces: []v1alpha1.WorkspaceTemplate{{Name: "source"}}},
+			err:  "workspace source: exactly one of",
+		},
+		{
+			spec: v1alpha1.GitHookSpec{
+				PipelineAPIVersion: v1alpha1.PipelineV1beta1,
+				EventTypes:         []v1alpha1.GitEvent{"push", "pull_request"},
+				Triggers: []v1alpha1.GitHookTrigger{
+					{Name: "test", EventTypes: []v1alpha1.GitEvent{"pull_request"}, PipelineRef: &tektonv1alpha1.PipelineRef{Name: "test"}},
+					{Name: "deploy", EventTypes: []v1alpha1.GitEvent{"push"}, PipelineRunSpec: pipelineRunSpec},
+				},
+			},
+			expected: "{}",
+		},
+		{
+			spec: v1alpha1.GitHookSpec{
+				EventTypes: []v1alpha1.GitEvent{"push"},
+				Triggers:   []v1alpha1.GitHookTrigger{{Name: "release", EventTypes: []v1alpha1.GitEvent{"release"}, PipelineRef: &tektonv1alpha1.PipelineRef{Name: "release"}}},
+			},
+			err: "trigger release: event type release is not one of eventTypes",
+		},
+		{
+			spec: v1alpha1.GitHookSpec{Triggers: []v1alpha1.GitHookTrigger{{Name: "deploy", PipelineRunSpec: pipelineRunSpec}}},
+			err:  "trigger deploy: pipelineRunSpec is only supported",
+		},
+		{
+			spec: v1alpha1.GitHookSpec{Triggers: []v1alpha1.GitHookTrigger{{Name: "deploy"}}},
+			err:  "trigger deploy: one of pipelineRef, runspec or pipelineRunSpec is required",
+		},
+		{
+			spec: v1alpha1.GitHookSpec{Triggers: []v1alpha1.GitHookTrigger{
+				{Name: "build", PipelineRef: &tektonv1alpha1.PipelineRef{Name: "build"}},
+				{Name: "build", PipelineRef: &tektonv1alpha1.PipelineRef{Name: "lint"}},
+			}},
+			err: "trigger build: name is not unique",
+		},
+	}
+
+	for i, test := range tests {
+		runSpecJSON, err := runSpecJSONFrom(&v1alpha1.GitHook{Spec: test.spec})
+
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("case %d: expected error %q but got %v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("case %d: unexpected error %s", i, err)
+		}
+
+		if !strings.Contains(runSpecJSON, test.expected) {
+			t.Fatalf("case %d: expected %s in %s", i, test.expected, runSpecJSON)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+const (
+	azureDevOpsAPIVersion       = "api-version=6.0"
+	azureDevOpsHookUsername     = "githook"
+	azureDevOpsChangesPageSize  = 1000
+	azureDevOpsSubscriptionsSep = ","
+)
+
+// AzureDevOpsClient provides azure devops repos client functionalities. A webhook is
+// a set of service hook subscriptions, one per event, identified by comma separated ids.
+type AzureDevOpsClient struct {
+	restClient *restClient
+}
+
+type azureDevOpsRepository struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Project struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+type azureDevOpsSubscription struct {
+	ID               string            `json:"id,omitempty"`
+	Status           string            `json:"status,omitempty"`
+	PublisherID      string            `json:"publisherId"`
+	EventType        string            `json:"eventType"`
+	ResourceVersion  string            `json:"resourceVersion"`
+	ConsumerID       string            `json:"consumerId"`
+	ConsumerActionID string            `json:"consumerActionId"`
+	PublisherInputs  map[string]string `json:"publisherInputs"`
+	ConsumerInputs   map[string]string `json:"consumerInputs"`
+}
+
+type azureDevOpsIterations struct {
+	Value []struct {
+		ID int `json:"id"`
+	} `json:"value"`
+}
+
+type azureDevOpsIterationChanges struct {
+	ChangeEntries []struct {
+		ChangeType   string `json:"changeType"`
+		OriginalPath string `json:"originalPath"`
+		Item         struct {
+			Path     string `json:"path"`
+			IsFolder bool   `json:"isFolder"`
+		} `json:"item"`
+	} `json:"changeEntries"`
+	NextSkip int `json:"nextSkip"`
+}
+
+// NewAzureDevOpsClient creates new azure devops client. Base url is the organization url
+// ex. https://dev.azure.com/myorg and access token is a personal access token.
+func NewAzureDevOpsClient(baseURL, accessToken string, httpClient *http.Client) *AzureDevOpsClient {
+	authorize := func(req *http.Request) {
+		req.SetBasicAuth("", accessToken)
+	}
+
+	return &AzureDevOpsClient{
+		restClient: newRestClient(baseURL, httpClient, authorize),
+	}
+}
+
+func azureDevOpsSubscriptionIDs(hookID string) []string {
+	if hookID == "" {
+		return nil
+	}
+
+	return strings.Split(hookID, azureDevOpsSubscriptionsSep)
+}
+
+func azureDevOpsRepoPath(options *model.HookOptions) string {
+	return fmt.Sprintf("/%s/_apis/git/repositories/%s", url.PathEscape(options.Owner), url.PathEscape(options.Project))
+}
+
+func azureDevOpsSubscriptionPath(id string) string {
+	return fmt.Sprintf("/_apis/hooks/subscriptions/%s?%s", url.PathEscape(id), azureDevOpsAPIVersion)
+}
+
+func (client *AzureDevOpsClient) getRepository(options *model.HookOptions) (*azureDevOpsRepository, error) {
+	repo := &azureDevOpsRepository{}
+	err := client.restClient.do(http.MethodGet, azureDevOpsRepoPath(options)+"?"+azureDevOpsAPIVersion, nil, repo)
+
+	return repo, err
+}
+
+// Validate checks if hook has been changed
+func (client *AzureDevOpsClient) Validate(options *model.HookOptions) (exists bool, changed bool, err error) {
+	ids := azureDevOpsSubscriptionIDs(options.ID)
+	if len(ids) == 0 {
+		return false, false, nil
+	}
+
+	eventSet := make(map[string]bool)
+
+	for _, id := range ids {
+		subscription := &azureDevOpsSubscription{}
+		err := client.restClient.do(http.MethodGet, azureDevOpsSubscriptionPath(id), nil, subscription)
+
+		if err != nil {
+			if isNotFound(err) {
+				// recreate all subscriptions when some of them are removed
+				return true, true, nil
+			}
+			return false, false, fmt.Errorf("Failed to get service hook subscription of the Project:" + options.Project + " due to " + err.Error())
+		}
+
+		if subscription.ConsumerInputs["url"] != options.URL || subscription.Status != "enabled" {
+			return true, true, nil
+		}
+
+		eventSet[subscription.EventType] = true
+	}
+
+	if len(eventSet) != len(options.Events) {
+		return true, true, nil
+	}
+
+	for _, event := range options.Events {
+		if eventSet[event] == false {
+			return true, true, nil
+		}
+	}
+
+	return true, false, nil
+}
+
+// Create creates a service hook subscription for each event
+func (client *AzureDevOpsClient) Create(options *model.HookOptions) (string, error) {
+	repo, err := client.getRepository(options)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get repository of the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	ids := make([]string, 0)
+
+	for _, event := range options.Events {
+		subscription := &azureDevOpsSubscription{
+			PublisherID:      "tfs",
+			EventType:        event,
+			ResourceVersion:  "1.0",
+			ConsumerID:       "webHooks",
+			ConsumerActionID: "httpRequest",
+			PublisherInputs: map[string]string{
+				"projectId":  repo.Project.ID,
+				"repository": repo.ID,
+			},
+			ConsumerInputs: map[string]string{
+				"url":                    options.URL,
+				"basicAuthUsername":      azureDevOpsHookUsername,
+				"basicAuthPassword":      options.SecretToken,
+				"resourceDetailsToSend":  "all",
+				"messagesToSend":         "none",
+				"detailedMessagesToSend": "none",
+			},
+		}
+
+		created := &azureDevOpsSubscription{}
+		err := client.restClient.do(http.MethodPost, "/_apis/hooks/subscriptions?"+azureDevOpsAPIVersion, subscription, created)
+
+		if err != nil {
+			// do not leave partially created webhook behind
+			client.deleteSubscriptions(ids)
+			return "", fmt.Errorf("Failed to add webhook to the Project:" + options.Project + " due to " + err.Error())
+		}
+
+		ids = append(ids, created.ID)
+	}
+
+	return strings.Join(ids, azureDevOpsSubscriptionsSep), nil
+}
+
+// Update replaces service hook subscriptions since subscriptions cannot change their event
+func (client *AzureDevOpsClient) Update(options *model.HookOptions) (string, error) {
+	if options.ID == "" {
+		return "", fmt.Errorf("webhook id is required to be updated")
+	}
+
+	if err := client.deleteSubscriptions(azureDevOpsSubscriptionIDs(options.ID)); err != nil {
+		return "", fmt.Errorf("Failed to update webhook to the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	return client.Create(options)
+}
+
+func (client *AzureDevOpsClient) deleteSubscriptions(ids []string) error {
+	for _, id := range ids {
+		err := client.restClient.do(http.MethodDelete, azureDevOpsSubscriptionPath(id), nil, nil)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete webhook
+func (client *AzureDevOpsClient) Delete(options *model.HookOptions) error {
+	if err := client.deleteSubscriptions(azureDevOpsSubscriptionIDs(options.ID)); err != nil {
+		return fmt.Errorf("failed to delete hook owner '%s' project '%s' : %s", options.Owner, options.Project, err)
+	}
+
+	return nil
+}
+
+// ListChangedFiles lists files changed by the latest iteration of pull request
+func (client *AzureDevOpsClient) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	prPath := fmt.Sprintf("%s/pullRequests/%d/iterations", azureDevOpsRepoPath(options), number)
+
+	iterations := &azureDevOpsIterations{}
+	if err := client.restClient.do(http.MethodGet, prPath+"?"+azureDevOpsAPIVersion, nil, iterations); err != nil {
+		return nil, fmt.Errorf("failed to list iterations of pull request %d of project '%s' : %s", number, options.Project, err)
+	}
+
+	files := make([]string, 0)
+	if len(iterations.Value) == 0 {
+		return files, nil
+	}
+
+	iteration := iterations.Value[len(iterations.Value)-1].ID
+
+	for skip := 0; ; {
+		changes := &azureDevOpsIterationChanges{}
+		path := fmt.Sprintf("%s/%d/changes?$top=%d&$skip=%d&%s", prPath, iteration, azureDevOpsChangesPageSize, skip, azureDevOpsAPIVersion)
+
+		if err := client.restClient.do(http.MethodGet, path, nil, changes); err != nil {
+			return nil, fmt.Errorf("failed to list files of pull request %d of project '%s' : %s", number, options.Project, err)
+		}
+
+		for _, change := range changes.ChangeEntries {
+			if change.Item.IsFolder {
+				continue
+			}
+
+			files = append(files, strings.TrimPrefix(change.Item.Path, "/"))
+			if change.OriginalPath != "" && change.OriginalPath != change.Item.Path {
+				files = append(files, strings.TrimPrefix(change.OriginalPath, "/"))
+			}
+		}
+
+		if changes.NextSkip == 0 {
+			break
+		}
+		skip = changes.NextSkip
+	}
+
+	return files, nil
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func TestAzureDevOpsCreate(t *testing.T) {
+	created := make([]*azureDevOpsSubscription, 0)
+	deleted := make([]string, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/myorg/My Project/_apis/git/repositories/app":
+			w.Write([]byte(`{"id":"repo-id","name":"app","project":{"id":"project-id","name":"My Project"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/myorg/_apis/hooks/subscriptions":
+			subscription := &azureDevOpsSubscription{}
+			json.NewDecoder(r.Body).Decode(subscription)
+
+			if subscription.EventType == "git.pullrequest.updated" {
+				http.Error(w, "quota exceeded", http.StatusBadRequest)
+				return
+			}
+
+			subscription.ID = subscription.EventType + "-id"
+			created = append(created, subscription)
+			json.NewEncoder(w).Encode(subscription)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewAzureDevOpsClient(server.URL+"/myorg", "token", http.DefaultClient)
+	options := &model.HookOptions{
+		Owner:       "My Project",
+		Project:     "app",
+		URL:         "http://hook.example.com",
+		SecretToken: "secret",
+		Events:      []string{"git.push"},
+	}
+
+	hookID, err := client.Create(options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if hookID != "git.push-id" {
+		t.Fatalf("expected hook id git.push-id but got %s", hookID)
+	}
+
+	subscription := created[0]
+	if subscription.PublisherInputs["projectId"] != "project-id" || subscription.PublisherInputs["repository"] != "repo-id" {
+		t.Fatalf("expected subscription on repository but got %v", subscription.PublisherInputs)
+	}
+
+	if subscription.ConsumerInputs["url"] != options.URL || subscription.ConsumerInputs["basicAuthPassword"] != "secret" {
+		t.Fatalf("expected subscription to send events to webhook with secret but got %v", subscription.ConsumerInputs)
+	}
+
+	options.Events = []string{"git.push", "git.pullrequest.created", "git.pullrequest.updated"}
+	if _, err := client.Create(options); err == nil {
+		t.Fatalf("expected error when subscription cannot be created")
+	}
+
+	if len(deleted) != 2 {
+		t.Fatalf("expected partially created subscriptions to be deleted but got %v", deleted)
+	}
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+const (
+	bitbucketAPIURL = "https://api.bitbucket.org/2.0"
+)
+
+// BitbucketClient provides bitbucket cloud git client functionalities
+type BitbucketClient struct {
+	restClient *restClient
+}
+
+type bitbucketHook struct {
+	UUID        string   `json:"uuid,omitempty"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Active      bool     `json:"active"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret,omitempty"`
+}
+
+type bitbucketDiffStat struct {
+	Values []struct {
+		Status string `json:"status"`
+		Old    *struct {
+			Path string `json:"path"`
+		} `json:"old"`
+		New *struct {
+			Path string `json:"path"`
+		} `json:"new"`
+	} `json:"values"`
+	Next string `json:"next"`
+}
+
+// bitbucketAuthorizer authorizes requests with basic auth when access token is given
+// as "username:password" otherwise access token is sent as bearer token
+func bitbucketAuthorizer(accessToken string) func(req *http.Request) {
+	return func(req *http.Request) {
+		if i := strings.Index(accessToken, ":"); i >= 0 {
+			req.SetBasicAuth(accessToken[:i], accessToken[i+1:])
+			return
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+}
+
+// NewBitbucketClient creates new bitbucket cloud git client. Access token is either
+// a repository or workspace access token or "username:app password".
+func NewBitbucketClient(accessToken string) *BitbucketClient {
+	return &BitbucketClient{
+		restClient: newRestClient(bitbucketAPIURL, http.DefaultClient, bitbucketAuthorizer(accessToken)),
+	}
+}
+
+func bitbucketRepoPath(options *model.HookOptions) string {
+	return fmt.Sprintf("/repositories/%s/%s", url.PathEscape(options.Owner), url.PathEscape(options.Project))
+}
+
+func bitbucketHookPath(options *model.HookOptions) string {
+	return fmt.Sprintf("%s/hooks/%s", bitbucketRepoPath(options), url.PathEscape(options.ID))
+}
+
+func newBitbucketHook(options *model.HookOptions) *bitbucketHook {
+	return &bitbucketHook{
+		URL:         options.URL,
+		Description: "githook",
+		Active:      true,
+		Events:      options.Events,
+		Secret:      options.SecretToken,
+	}
+}
+
+// Validate checks if hook has been changed
+func (client *BitbucketClient) Validate(options *model.HookOptions) (exists bool, changed bool, err error) {
+	if options.ID == "" {
+		return false, false, nil
+	}
+
+	hook := &bitbucketHook{}
+	err = client.restClient.do(http.MethodGet, bitbucketHookPath(options), nil, hook)
+
+	if err != nil {
+		if isNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("Failed to get webhook of the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	if hook.URL != options.URL || !hook.Active {
+		return true, true, nil
+	}
+
+	if len(hook.Events) != len(options.Events) {
+		return true, true, nil
+	}
+
+	eventSet := make(map[string]bool)
+
+	for _, event := range hook.Events {
+		eventSet[event] = true
+	}
+
+	for _, event := range options.Events {
+		if eventSet[event] == false {
+			return true, true, nil
+		}
+	}
+
+	return true, false, nil
+}
+
+// Create creates webhook
+func (client *BitbucketClient) Create(options *model.HookOptions) (string, error) {
+	hook := &bitbucketHook{}
+
+	err := client.restClient.do(http.MethodPost, bitbucketRepoPath(options)+"/hooks", newBitbucketHook(options), hook)
+	if err != nil {
+		return "", fmt.Errorf("Failed to add webhook to the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	return hook.UUID, nil
+}
+
+// Update updates webhook
+func (client *BitbucketClient) Update(options *model.HookOptions) (string, error) {
+	if options.ID == "" {
+		return "", fmt.Errorf("webhook id is required to be updated")
+	}
+
+	hook := &bitbucketHook{}
+
+	err := client.restClient.do(http.MethodPut, bitbucketHookPath(options), newBitbucketHook(options), hook)
+	if err != nil {
+		return "", fmt.Errorf("Failed to update webhook to the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	return hook.UUID, nil
+}
+
+// Delete webhook
+func (client *BitbucketClient) Delete(options *model.HookOptions) error {
+	if options.ID != "" {
+		err := client.restClient.do(http.MethodDelete, bitbucketHookPath(options), nil, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to delete hook owner '%s' project '%s' : %s", options.Owner, options.Project, err)
+		}
+	}
+
+	return nil
+}
+
+// ListChangedFiles lists files changed by pull request
+func (client *BitbucketClient) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	files := make([]string, 0)
+	next := fmt.Sprintf("%s/pullrequests/%d/diffstat?pagelen=100", bitbucketRepoPath(options), number)
+
+	for next != "" {
+		diffStat := &bitbucketDiffStat{}
+
+		if err := client.restClient.do(http.MethodGet, next, nil, diffStat); err != nil {
+			return nil, fmt.Errorf("failed to list files of pull request %d of project '%s' : %s", number, options.Project, err)
+		}
+
+		for _, value := range diffStat.Values {
+			if value.New != nil {
+				files = append(files, value.New.Path)
+			}
+			if value.Old != nil && (value.New == nil || value.Old.Path != value.New.Path) {
+				files = append(files, value.Old.Path)
+			}
+		}
+
+		next = diffStat.Next
+	}
+
+	return files, nil
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func newTestBitbucketClient(handler http.HandlerFunc) (*BitbucketClient, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	client := NewBitbucketClient("token")
+	client.restClient.baseURL = server.URL
+
+	return client, server
+}
+
+func TestBitbucketCreate(t *testing.T) {
+	client, server := newTestBitbucketClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/team/repo/hooks" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Fatalf("expected bearer token but got %s", r.Header.Get("Authorization"))
+		}
+
+		hook := &bitbucketHook{}
+		json.NewDecoder(r.Body).Decode(hook)
+
+		expectedEvents := []string{"repo:push", "pullrequest:created", "pullrequest:updated"}
+		if !reflect.DeepEqual(hook.Events, expectedEvents) {
+			t.Fatalf("expected events %v but got %v", expectedEvents, hook.Events)
+		}
+
+		hook.UUID = "{1234}"
+		json.NewEncoder(w).Encode(hook)
+	})
+	defer server.Close()
+
+	hookID, err := client.Create(&model.HookOptions{
+		Owner:   "team",
+		Project: "repo",
+		URL:     "http://hook.example.com",
+		Events:  []string{"repo:push", "pullrequest:created", "pullrequest:updated"},
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if hookID != "{1234}" {
+		t.Fatalf("expected hook id {1234} but got %s", hookID)
+	}
+}
+
+func TestBitbucketValidate(t *testing.T) {
+	client, server := newTestBitbucketClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/team/repo/hooks/{1234}" {
+			http.NotFound(w, r)
+			return
+		}
+
+		json.NewEncoder(w).Encode(&bitbucketHook{
+			UUID:   "{1234}",
+			URL:    "http://hook.example.com",
+			Active: true,
+			Events: []string{"repo:push"},
+		})
+	})
+	defer server.Close()
+
+	tests := []struct {
+		id      string
+		url     string
+		events  []string
+		exists  bool
+		changed bool
+	}{
+		{id: "{1234}", url: "http://hook.example.com", events: []string{"repo:push"}, exists: true, changed: false},
+		{id: "{1234}", url: "http://hook2.example.com", events: []string{"repo:push"}, exists: true, changed: true},
+		{id: "{1234}", url: "http://hook.example.com", events: []string{"repo:push", "pullrequest:created"}, exists: true, changed: true},
+		{id: "{5678}", url: "http://hook.example.com", events: []string{"repo:push"}, exists: false, changed: false},
+	}
+
+	for _, test := range tests {
+		exists, changed, err := client.Validate(&model.HookOptions{
+			ID:      test.id,
+			Owner:   "team",
+			Project: "repo",
+			URL:     test.url,
+			Events:  test.events,
+		})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if exists != test.exists || changed != test.changed {
+			t.Fatalf("expected exists %v and changed %v but got %v and %v", test.exists, test.changed, exists, changed)
+		}
+	}
+}
+
+func TestBitbucketListChangedFiles(t *testing.T) {
+	var serverURL string
+	client, server := newTestBitbucketClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`{"values":[{"status":"renamed","old":{"path":"a.go"},"new":{"path":"b.go"}}]}`))
+			return
+		}
+
+		w.Write([]byte(`{"values":[{"status":"added","new":{"path":"main.go"}},{"status":"removed","old":{"path":"old.go"}}],"next":"` + serverURL + `/repositories/team/repo/pullrequests/1/diffstat?page=2"}`))
+	})
+	defer server.Close()
+	serverURL = server.URL
+
+	files, err := client.ListChangedFiles(&model.HookOptions{Owner: "team", Project: "repo"}, 1)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expectedFiles := []string{"main.go", "old.go", "b.go", "a.go"}
+	if !reflect.DeepEqual(files, expectedFiles) {
+		t.Fatalf("expected files %v but got %v", expectedFiles, files)
+	}
+}
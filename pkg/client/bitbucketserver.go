@@ -0,0 +1,178 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+const (
+	bitbucketServerChangesPageSize = 500
+)
+
+// BitbucketServerClient provides bitbucket server and data center git client functionalities
+type BitbucketServerClient struct {
+	restClient *restClient
+}
+
+type bitbucketServerWebhook struct {
+	ID            int64             `json:"id,omitempty"`
+	Name          string            `json:"name"`
+	URL           string            `json:"url"`
+	Active        bool              `json:"active"`
+	Events        []string          `json:"events"`
+	Configuration map[string]string `json:"configuration"`
+}
+
+type bitbucketServerChanges struct {
+	Values []struct {
+		Type string `json:"type"`
+		Path struct {
+			ToString string `json:"toString"`
+		} `json:"path"`
+		SrcPath *struct {
+			ToString string `json:"toString"`
+		} `json:"srcPath"`
+	} `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// NewBitbucketServerClient creates new bitbucket server git client. Access token is either
+// a http access token or "username:password".
+func NewBitbucketServerClient(baseURL, accessToken string, httpClient *http.Client) *BitbucketServerClient {
+	return &BitbucketServerClient{
+		restClient: newRestClient(strings.TrimSuffix(baseURL, "/")+"/rest/api/1.0", httpClient, bitbucketAuthorizer(accessToken)),
+	}
+}
+
+func bitbucketServerRepoPath(options *model.HookOptions) string {
+	return fmt.Sprintf("/projects/%s/repos/%s", url.PathEscape(options.Owner), url.PathEscape(options.Project))
+}
+
+func bitbucketServerHookPath(options *model.HookOptions) string {
+	return fmt.Sprintf("%s/webhooks/%s", bitbucketServerRepoPath(options), url.PathEscape(options.ID))
+}
+
+func newBitbucketServerWebhook(options *model.HookOptions) *bitbucketServerWebhook {
+	return &bitbucketServerWebhook{
+		Name:   "githook",
+		URL:    options.URL,
+		Active: true,
+		Events: options.Events,
+		Configuration: map[string]string{
+			"secret": options.SecretToken,
+		},
+	}
+}
+
+// Validate checks if hook has been changed
+func (client *BitbucketServerClient) Validate(options *model.HookOptions) (exists bool, changed bool, err error) {
+	if options.ID == "" {
+		return false, false, nil
+	}
+
+	hook := &bitbucketServerWebhook{}
+	err = client.restClient.do(http.MethodGet, bitbucketServerHookPath(options), nil, hook)
+
+	if err != nil {
+		if isNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("Failed to get webhook of the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	if hook.URL != options.URL || !hook.Active {
+		return true, true, nil
+	}
+
+	if len(hook.Events) != len(options.Events) {
+		return true, true, nil
+	}
+
+	eventSet := make(map[string]bool)
+
+	for _, event := range hook.Events {
+		eventSet[event] = true
+	}
+
+	for _, event := range options.Events {
+		if eventSet[event] == false {
+			return true, true, nil
+		}
+	}
+
+	return true, false, nil
+}
+
+// Create creates webhook
+func (client *BitbucketServerClient) Create(options *model.HookOptions) (string, error) {
+	hook := &bitbucketServerWebhook{}
+
+	err := client.restClient.do(http.MethodPost, bitbucketServerRepoPath(options)+"/webhooks", newBitbucketServerWebhook(options), hook)
+	if err != nil {
+		return "", fmt.Errorf("Failed to add webhook to the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	return strconv.FormatInt(hook.ID, 10), nil
+}
+
+// Update updates webhook
+func (client *BitbucketServerClient) Update(options *model.HookOptions) (string, error) {
+	if options.ID == "" {
+		return "", fmt.Errorf("webhook id is required to be updated")
+	}
+
+	hook := &bitbucketServerWebhook{}
+
+	err := client.restClient.do(http.MethodPut, bitbucketServerHookPath(options), newBitbucketServerWebhook(options), hook)
+	if err != nil {
+		return "", fmt.Errorf("Failed to update webhook to the Project:" + options.Project + " due to " + err.Error())
+	}
+
+	return strconv.FormatInt(hook.ID, 10), nil
+}
+
+// Delete webhook
+func (client *BitbucketServerClient) Delete(options *model.HookOptions) error {
+	if options.ID != "" {
+		err := client.restClient.do(http.MethodDelete, bitbucketServerHookPath(options), nil, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to delete hook owner '%s' project '%s' : %s", options.Owner, options.Project, err)
+		}
+	}
+
+	return nil
+}
+
+// ListChangedFiles lists files changed by pull request
+func (client *BitbucketServerClient) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	files := make([]string, 0)
+
+	for start := 0; ; {
+		changes := &bitbucketServerChanges{}
+		path := fmt.Sprintf("%s/pull-requests/%d/changes?start=%d&limit=%d", bitbucketServerRepoPath(options), number, start, bitbucketServerChangesPageSize)
+
+		if err := client.restClient.do(http.MethodGet, path, nil, changes); err != nil {
+			return nil, fmt.Errorf("failed to list files of pull request %d of project '%s' : %s", number, options.Project, err)
+		}
+
+		for _, change := range changes.Values {
+			files = append(files, change.Path.ToString)
+			if change.SrcPath != nil && change.SrcPath.ToString != change.Path.ToString {
+				files = append(files, change.SrcPath.ToString)
+			}
+		}
+
+		if changes.IsLastPage || changes.NextPageStart <= start {
+			break
+		}
+		start = changes.NextPageStart
+	}
+
+	return files, nil
+}
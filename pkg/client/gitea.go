@@ -0,0 +1,283 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+const (
+	giteaFilesPageSize = 50
+)
+
+// GiteaClient provides gitea git client functionalities
+type GiteaClient struct {
+	restClient *restClient
+}
+
+type giteaHookConfig struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
+}
+
+type giteaHook struct {
+	ID     int64           `json:"id,omitempty"`
+	Type   string          `json:"type,omitempty"`
+	Config giteaHookConfig `json:"config"`
+	Events []string        `json:"events"`
+	Active bool            `json:"active"`
+}
+
+type giteaCommitStatus struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+	Context     string `json:"context,omitempty"`
+}
+
+type giteaChangedFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+}
+
+// NewGiteaClient creates new gitea git client
+func NewGiteaClient(baseURL, accessToken string, httpClient *http.Client) *GiteaClient {
+	authorize := func(req *http.Request) {
+		req.Header.Set("Authorization", "token "+accessToken)
+	}
+
+	return &GiteaClient{
+		restClient: newRestClient(strings.TrimSuffix(baseURL, "/")+"/api/v1", httpClient, authorize),
+	}
+}
+
+func giteaRepoPath(options *model.HookOptions) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(options.Owner), url.PathEscape(options.Project))
+}
+
+func giteaRepoHooksPath(options *model.HookOptions) string {
+	return giteaRepoPath(options) + "/hooks"
+}
+
+func giteaOrgHooksPath(options *model.HookOptions) string {
+	return fmt.Sprintf("/orgs/%s/hooks", url.PathEscape(options.Owner))
+}
+
+// giteaEventGroup returns the event type a hook is registered with for an event
+// reported by gitea ex. pull_request_assign is registered as pull_request
+func giteaEventGroup(event string) string {
+	switch {
+	case strings.HasPrefix(event, "pull_request_review"):
+		return "pull_request_review"
+	case strings.HasPrefix(event, "pull_request"):
+		return "pull_request"
+	case strings.HasPrefix(event, "issue_") && event != "issue_comment":
+		return "issues"
+	}
+
+	return event
+}
+
+func newGiteaHook(options *model.HookOptions) *giteaHook {
+	return &giteaHook{
+		Type: "gitea",
+		Config: giteaHookConfig{
+			URL:         options.URL,
+			ContentType: "json",
+			Secret:      options.SecretToken,
+		},
+		Events: options.Events,
+		Active: true,
+	}
+}
+
+// Validate checks if hook has been changed
+func (client *GiteaClient) Validate(options *model.HookOptions) (exists bool, changed bool, err error) {
+	return client.validate(options, giteaRepoHooksPath(options))
+}
+
+// ValidateOrgHook checks if organization hook has been changed
+func (client *GiteaClient) ValidateOrgHook(options *model.HookOptions) (exists bool, changed bool, err error) {
+	return client.validate(options, giteaOrgHooksPath(options))
+}
+
+func (client *GiteaClient) validate(options *model.HookOptions, hooksPath string) (exists bool, changed bool, err error) {
+	if options.ID == "" {
+		return false, false, nil
+	}
+
+	hook := &giteaHook{}
+	err = client.restClient.do(http.MethodGet, hooksPath+"/"+url.PathEscape(options.ID), nil, hook)
+
+	if err != nil {
+		if isNotFound(err) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("Failed to get webhook %s due to %s", hooksPath, err)
+	}
+
+	if hook.Config.URL != options.URL || !hook.Active {
+		return true, true, nil
+	}
+
+	// gitea reports events in detail so compare them by group
+	expected := make(map[string]bool)
+	for _, event := range options.Events {
+		expected[event] = true
+	}
+
+	// pull_request also subscribes to pull request reviews
+	if expected["pull_request"] {
+		expected["pull_request_review"] = true
+	}
+
+	actual := make(map[string]bool)
+	for _, event := range hook.Events {
+		actual[giteaEventGroup(event)] = true
+	}
+
+	if len(actual) != len(expected) {
+		return true, true, nil
+	}
+
+	for event := range expected {
+		if actual[event] == false {
+			return true, true, nil
+		}
+	}
+
+	return true, false, nil
+}
+
+// Create creates webhook
+func (client *GiteaClient) Create(options *model.HookOptions) (string, error) {
+	return client.create(options, giteaRepoHooksPath(options))
+}
+
+// CreateOrgHook creates organization webhook
+func (client *GiteaClient) CreateOrgHook(options *model.HookOptions) (string, error) {
+	return client.create(options, giteaOrgHooksPath(options))
+}
+
+func (client *GiteaClient) create(options *model.HookOptions, hooksPath string) (string, error) {
+	hook := &giteaHook{}
+
+	err := client.restClient.do(http.MethodPost, hooksPath, newGiteaHook(options), hook)
+	if err != nil {
+		return "", fmt.Errorf("Failed to add webhook %s due to %s", hooksPath, err)
+	}
+
+	return strconv.FormatInt(hook.ID, 10), nil
+}
+
+// Update updates webhook
+func (client *GiteaClient) Update(options *model.HookOptions) (string, error) {
+	return client.update(options, giteaRepoHooksPath(options))
+}
+
+// UpdateOrgHook updates organization webhook
+func (client *GiteaClient) UpdateOrgHook(options *model.HookOptions) (string, error) {
+	return client.update(options, giteaOrgHooksPath(options))
+}
+
+func (client *GiteaClient) update(options *model.HookOptions, hooksPath string) (string, error) {
+	if options.ID == "" {
+		return "", fmt.Errorf("webhook id is required to be updated")
+	}
+
+	hook := &giteaHook{}
+
+	err := client.restClient.do(http.MethodPatch, hooksPath+"/"+url.PathEscape(options.ID), newGiteaHook(options), hook)
+	if err != nil {
+		return "", fmt.Errorf("Failed to update webhook %s due to %s", hooksPath, err)
+	}
+
+	return strconv.FormatInt(hook.ID, 10), nil
+}
+
+// Delete webhook
+func (client *GiteaClient) Delete(options *model.HookOptions) error {
+	return client.delete(options, giteaRepoHooksPath(options))
+}
+
+// DeleteOrgHook deletes organization webhook
+func (client *GiteaClient) DeleteOrgHook(options *model.HookOptions) error {
+	return client.delete(options, giteaOrgHooksPath(options))
+}
+
+func (client *GiteaClient) delete(options *model.HookOptions, hooksPath string) error {
+	if options.ID != "" {
+		err := client.restClient.do(http.MethodDelete, hooksPath+"/"+url.PathEscape(options.ID), nil, nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to delete hook %s : %s", hooksPath, err)
+		}
+	}
+
+	return nil
+}
+
+// ListChangedFiles lists files changed by pull request. It is not supported by gitea older than 1.17.
+func (client *GiteaClient) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	files := make([]string, 0)
+
+	for page := 1; ; page++ {
+		changedFiles := make([]giteaChangedFile, 0)
+		path := fmt.Sprintf("%s/pulls/%d/files?page=%d&limit=%d", giteaRepoPath(options), number, page, giteaFilesPageSize)
+
+		if err := client.restClient.do(http.MethodGet, path, nil, &changedFiles); err != nil {
+			if isNotFound(err) {
+				return nil, model.ErrNotSupported
+			}
+			return nil, fmt.Errorf("failed to list files of pull request %d of project '%s' : %s", number, options.Project, err)
+		}
+
+		for _, file := range changedFiles {
+			files = append(files, file.Filename)
+			if file.PreviousFilename != "" && file.PreviousFilename != file.Filename {
+				files = append(files, file.PreviousFilename)
+			}
+		}
+
+		if len(changedFiles) < giteaFilesPageSize {
+			break
+		}
+	}
+
+	return files, nil
+}
+
+// giteaStatusState maps state of pipeline run to gitea commit status
+func giteaStatusState(state model.CommitState) string {
+	switch state {
+	case model.CommitStateSuccess:
+		return "success"
+	case model.CommitStateFailure:
+		return "failure"
+	case model.CommitStateCancelled:
+		return "error"
+	}
+
+	return "pending"
+}
+
+// SetCommitStatus reports status of pipeline run on commit
+func (client *GiteaClient) SetCommitStatus(options *model.HookOptions, status *model.CommitStatus) error {
+	commitStatus := &giteaCommitStatus{
+		State:       giteaStatusState(status.State),
+		TargetURL:   status.TargetURL,
+		Description: status.Description,
+		Context:     status.Context,
+	}
+
+	path := fmt.Sprintf("%s/statuses/%s", giteaRepoPath(options), url.PathEscape(status.SHA))
+	if err := client.restClient.do(http.MethodPost, path, commitStatus, nil); err != nil {
+		return fmt.Errorf("failed to set status of commit %s of project '%s' : %s", status.SHA, options.Project, err)
+	}
+
+	return nil
+}
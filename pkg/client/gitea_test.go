@@ -0,0 +1,96 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func TestGiteaValidate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/team/app/hooks/7" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "token token" {
+			t.Fatalf("expected access token but got %s", r.Header.Get("Authorization"))
+		}
+
+		json.NewEncoder(w).Encode(&giteaHook{
+			ID:     7,
+			Config: giteaHookConfig{URL: "http://hook.example.com"},
+			Active: true,
+			Events: []string{"push", "pull_request", "pull_request_assign", "pull_request_review_approved", "pull_request_sync"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewGiteaClient(server.URL, "token", http.DefaultClient)
+
+	tests := []struct {
+		id      string
+		url     string
+		events  []string
+		exists  bool
+		changed bool
+	}{
+		{id: "7", url: "http://hook.example.com", events: []string{"push", "pull_request"}, exists: true, changed: false},
+		{id: "7", url: "http://hook.example.com", events: []string{"push", "pull_request", "pull_request_review"}, exists: true, changed: false},
+		{id: "7", url: "http://hook.example.com", events: []string{"push"}, exists: true, changed: true},
+		{id: "7", url: "http://hook.example.com", events: []string{"push", "pull_request", "release"}, exists: true, changed: true},
+		{id: "7", url: "http://hook2.example.com", events: []string{"push", "pull_request"}, exists: true, changed: true},
+		{id: "8", url: "http://hook.example.com", events: []string{"push", "pull_request"}, exists: false, changed: false},
+	}
+
+	for i, test := range tests {
+		exists, changed, err := client.Validate(&model.HookOptions{
+			ID:      test.id,
+			Owner:   "team",
+			Project: "app",
+			URL:     test.url,
+			Events:  test.events,
+		})
+
+		if err != nil {
+			t.Fatalf("case %d: unexpected error: %s", i, err)
+		}
+
+		if exists != test.exists || changed != test.changed {
+			t.Fatalf("case %d: expected exists %v and changed %v but got %v and %v", i, test.exists, test.changed, exists, changed)
+		}
+	}
+}
+
+func TestGiteaCommitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/repos/team/app/statuses/abc123" {
+			http.NotFound(w, r)
+			return
+		}
+
+		status := &giteaCommitStatus{}
+		json.NewDecoder(r.Body).Decode(status)
+
+		if status.State != "pending" || status.Context != "githook/app" {
+			t.Errorf("unexpected status %v", status)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewGiteaClient(server.URL, "token", http.DefaultClient)
+
+	err := client.SetCommitStatus(&model.HookOptions{Owner: "team", Project: "app"}, &model.CommitStatus{
+		SHA:     "abc123",
+		State:   model.CommitStateRunning,
+		Context: "githook/app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,33 +3,70 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/go-github/v26/github"
 	"gitlab.com/pongsatt/githook/pkg/model"
 	"golang.org/x/oauth2"
 )
 
+const (
+	githubHost = "github.com"
+)
+
 // GithubClient provides github git client functionalities
 type GithubClient struct {
 	authenticatedCtx context.Context
 	githubClient     *github.Client
 }
 
-// NewGithubClient creates new github git client
-func NewGithubClient(accessToken string) *GithubClient {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: accessToken},
-	)
+// isGithubDotCom checks if base url is github.com rather than github enterprise server
+func isGithubDotCom(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return true
+	}
+
+	return u.Hostname() == githubHost || u.Hostname() == "www."+githubHost || u.Hostname() == "api."+githubHost
+}
+
+// newGithubAPIClient creates github api client using http client tc. Github enterprise server api
+// is used when base url is not github.com.
+func newGithubAPIClient(baseURL string, tc *http.Client) (*github.Client, error) {
+	if isGithubDotCom(baseURL) {
+		return github.NewClient(tc), nil
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return github.NewEnterpriseClient(baseURL+"/api/v3/", baseURL+"/api/uploads/", tc)
+}
+
+func newGithubClient(baseURL string, ts oauth2.TokenSource, httpClient *http.Client) (*GithubClient, error) {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
 	tc := oauth2.NewClient(ctx, ts)
 
-	githubClient := github.NewClient(tc)
+	githubClient, err := newGithubAPIClient(baseURL, tc)
+	if err != nil {
+		return nil, err
+	}
 
 	return &GithubClient{
 		authenticatedCtx: ctx,
 		githubClient:     githubClient,
-	}
+	}, nil
+}
+
+// NewGithubClient creates new github git client authenticated with access token
+func NewGithubClient(baseURL, accessToken string, httpClient *http.Client) (*GithubClient, error) {
+	ts := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: accessToken},
+	)
+
+	return newGithubClient(baseURL, ts, httpClient)
 }
 
 // Validate checks if hook has been changed
@@ -48,12 +85,17 @@ func (client *GithubClient) Validate(options *model.HookOptions) (exists bool, c
 		return false, false, nil
 	}
 
+	return true, githubHookChanged(hook, options), nil
+}
+
+// githubHookChanged checks if url or events of hook differ from options
+func githubHookChanged(hook *github.Hook, options *model.HookOptions) bool {
 	if hook.Config["url"] != options.URL {
-		return true, true, nil
+		return true
 	}
 
 	if len(hook.Events) != len(options.Events) {
-		return true, true, nil
+		return true
 	}
 
 	eventSet := make(map[string]bool)
@@ -64,11 +106,22 @@ func (client *GithubClient) Validate(options *model.HookOptions) (exists bool, c
 
 	for _, event := range options.Events {
 		if eventSet[event] == false {
-			return true, true, nil
+			return true
 		}
 	}
 
-	return true, false, nil
+	return false
+}
+
+func newGithubHook(options *model.HookOptions) *github.Hook {
+	return &github.Hook{
+		Config: map[string]interface{}{
+			"content_type": "json",
+			"url":          options.URL,
+			"secret":       options.SecretToken,
+		},
+		Events: options.Events,
+	}
 }
 
 func (client *GithubClient) getHook(options *model.HookOptions) (*github.Hook, error) {
@@ -88,14 +141,7 @@ func (client *GithubClient) getHook(options *model.HookOptions) (*github.Hook, e
 
 // Create creates webhook
 func (client *GithubClient) Create(options *model.HookOptions) (string, error) {
-	hookOptions := &github.Hook{
-		Config: map[string]interface{}{
-			"content_type": "json",
-			"url":          options.URL,
-			"secret":       options.SecretToken,
-		},
-		Events: options.Events,
-	}
+	hookOptions := newGithubHook(options)
 
 	hook, _, err := client.githubClient.Repositories.CreateHook(client.authenticatedCtx, options.Owner, options.Project, hookOptions)
 	if err != nil {
@@ -117,14 +163,7 @@ func (client *GithubClient) Update(options *model.HookOptions) (string, error) {
 		return "", fmt.Errorf("webhook id is required to be updated")
 	}
 
-	hookOptions := &github.Hook{
-		Config: map[string]interface{}{
-			"content_type": "json",
-			"url":          options.URL,
-			"secret":       options.SecretToken,
-		},
-		Events: options.Events,
-	}
+	hookOptions := newGithubHook(options)
 
 	hookID, err := strconv.Atoi(options.ID)
 
@@ -157,3 +196,203 @@ func (client *GithubClient) Delete(options *model.HookOptions) error {
 
 	return nil
 }
+
+// ListChangedFiles lists files changed by pull request
+func (client *GithubClient) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	files := make([]string, 0)
+	listOptions := &github.ListOptions{PerPage: 100}
+
+	for {
+		commitFiles, resp, err := client.githubClient.PullRequests.ListFiles(client.authenticatedCtx, options.Owner, options.Project, number, listOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files of pull request %d of project '%s' : %s", number, options.Project, err)
+		}
+
+		for _, commitFile := range commitFiles {
+			files = append(files, commitFile.GetFilename())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		listOptions.Page = resp.NextPage
+	}
+
+	return files, nil
+}
+
+// ValidateOrgHook checks if organization hook has been changed
+func (client *GithubClient) ValidateOrgHook(options *model.HookOptions) (exists bool, changed bool, err error) {
+	if options.ID == "" {
+		return false, false, nil
+	}
+
+	hookID, err := strconv.ParseInt(options.ID, 10, 64)
+
+	if err != nil {
+		return false, false, err
+	}
+
+	hook, resp, err := client.githubClient.Organizations.GetHook(client.authenticatedCtx, options.Owner, hookID)
+
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("failed to get webhook of organization '%s' : %s", options.Owner, err)
+	}
+
+	return true, githubHookChanged(hook, options), nil
+}
+
+// CreateOrgHook creates organization webhook
+func (client *GithubClient) CreateOrgHook(options *model.HookOptions) (string, error) {
+	hook, _, err := client.githubClient.Organizations.CreateHook(client.authenticatedCtx, options.Owner, newGithubHook(options))
+	if err != nil {
+		return "", fmt.Errorf("failed to add webhook to organization '%s' : %s", options.Owner, err)
+	}
+
+	return strconv.FormatInt(hook.GetID(), 10), nil
+}
+
+// UpdateOrgHook updates organization webhook
+func (client *GithubClient) UpdateOrgHook(options *model.HookOptions) (string, error) {
+	hookID, err := strconv.ParseInt(options.ID, 10, 64)
+
+	if err != nil {
+		return "", fmt.Errorf("cannot convert hook ID %s", options.ID)
+	}
+
+	hook, _, err := client.githubClient.Organizations.EditHook(client.authenticatedCtx, options.Owner, hookID, newGithubHook(options))
+	if err != nil {
+		return "", fmt.Errorf("failed to update webhook of organization '%s' : %s", options.Owner, err)
+	}
+
+	return strconv.FormatInt(hook.GetID(), 10), nil
+}
+
+// DeleteOrgHook deletes organization webhook
+func (client *GithubClient) DeleteOrgHook(options *model.HookOptions) error {
+	if options.ID != "" {
+		hookID, err := strconv.ParseInt(options.ID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to convert hook id to int: " + err.Error())
+		}
+
+		_, err = client.githubClient.Organizations.DeleteHook(client.authenticatedCtx, options.Owner, hookID)
+		if err != nil {
+			return fmt.Errorf("failed to delete hook of organization '%s' : %s", options.Owner, err)
+		}
+	}
+
+	return nil
+}
+
+// githubStatusState maps state of pipeline run to github commit status
+func githubStatusState(state model.CommitState) string {
+	switch state {
+	case model.CommitStateSuccess:
+		return "success"
+	case model.CommitStateFailure:
+		return "failure"
+	case model.CommitStateCancelled:
+		return "error"
+	}
+
+	return "pending"
+}
+
+// githubCheckRunState maps state of pipeline run to github check run status and conclusion
+func githubCheckRunState(state model.CommitState) (status string, conclusion string) {
+	switch state {
+	case model.CommitStatePending:
+		return "queued", ""
+	case model.CommitStateRunning:
+		return "in_progress", ""
+	case model.CommitStateSuccess:
+		return "completed", "success"
+	case model.CommitStateCancelled:
+		return "completed", "cancelled"
+	}
+
+	return "completed", "failure"
+}
+
+// SetCommitStatus reports status of pipeline run on commit
+func (client *GithubClient) SetCommitStatus(options *model.HookOptions, status *model.CommitStatus) error {
+	repoStatus := &github.RepoStatus{
+		State:       github.String(githubStatusState(status.State)),
+		Description: github.String(status.Description),
+		Context:     github.String(status.Context),
+	}
+
+	if status.TargetURL != "" {
+		repoStatus.TargetURL = github.String(status.TargetURL)
+	}
+
+	_, _, err := client.githubClient.Repositories.CreateStatus(client.authenticatedCtx, options.Owner, options.Project, status.SHA, repoStatus)
+	if err != nil {
+		return fmt.Errorf("failed to set status of commit %s of project '%s' : %s", status.SHA, options.Project, err)
+	}
+
+	return nil
+}
+
+// SetCheckRun creates or updates check run of pipeline run on commit. Check runs can only
+// be created by github apps.
+func (client *GithubClient) SetCheckRun(options *model.HookOptions, status *model.CommitStatus) (string, error) {
+	checkStatus, conclusion := githubCheckRunState(status.State)
+	output := &github.CheckRunOutput{
+		Title:   github.String(status.Description),
+		Summary: github.String(status.Description),
+	}
+
+	var detailsURL, conclusionValue *string
+	var completedAt *github.Timestamp
+
+	if status.TargetURL != "" {
+		detailsURL = github.String(status.TargetURL)
+	}
+
+	if conclusion != "" {
+		conclusionValue = github.String(conclusion)
+		completedAt = &github.Timestamp{Time: time.Now()}
+	}
+
+	if status.CheckRunID == "" {
+		checkRun, _, err := client.githubClient.Checks.CreateCheckRun(client.authenticatedCtx, options.Owner, options.Project, github.CreateCheckRunOptions{
+			Name:        status.Context,
+			HeadBranch:  status.Branch,
+			HeadSHA:     status.SHA,
+			DetailsURL:  detailsURL,
+			Status:      github.String(checkStatus),
+			Conclusion:  conclusionValue,
+			CompletedAt: completedAt,
+			Output:      output,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create check run of commit %s of project '%s' : %s", status.SHA, options.Project, err)
+		}
+
+		return strconv.FormatInt(checkRun.GetID(), 10), nil
+	}
+
+	checkRunID, err := strconv.ParseInt(status.CheckRunID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid check run id %s", status.CheckRunID)
+	}
+
+	_, _, err = client.githubClient.Checks.UpdateCheckRun(client.authenticatedCtx, options.Owner, options.Project, checkRunID, github.UpdateCheckRunOptions{
+		Name:        status.Context,
+		DetailsURL:  detailsURL,
+		Status:      github.String(checkStatus),
+		Conclusion:  conclusionValue,
+		CompletedAt: completedAt,
+		Output:      output,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update check run %s of project '%s' : %s", status.CheckRunID, options.Project, err)
+	}
+
+	return status.CheckRunID, nil
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func TestNewGithubClientBaseURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		apiURL  string
+	}{
+		{baseURL: "https://github.com", apiURL: "https://api.github.com/"},
+		{baseURL: "", apiURL: "https://api.github.com/"},
+		{baseURL: "https://github.example.com", apiURL: "https://github.example.com/api/v3/"},
+		{baseURL: "https://github.example.com:8443/", apiURL: "https://github.example.com:8443/api/v3/"},
+	}
+
+	for _, test := range tests {
+		client, err := NewGithubClient(test.baseURL, "token", http.DefaultClient)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if apiURL := client.githubClient.BaseURL.String(); apiURL != test.apiURL {
+			t.Errorf("expected api url %s for %s but got %s", test.apiURL, test.baseURL, apiURL)
+		}
+	}
+}
+
+func TestGithubOrgHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/orgs/myorg/hooks":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": 11}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/orgs/myorg/hooks/11":
+			fmt.Fprint(w, `{"id": 11, "events": ["push"], "config": {"url": "http://hook.example.com"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewGithubClient(server.URL, "token", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &model.HookOptions{
+		Owner:        "myorg",
+		Organization: true,
+		URL:          "http://hook.example.com",
+		Events:       []string{"push"},
+	}
+
+	hookID, err := client.CreateOrgHook(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hookID != "11" {
+		t.Fatalf("expected hook id 11 but got %s", hookID)
+	}
+
+	options.ID = hookID
+	options.Events = []string{"push", "pull_request"}
+	exists, changed, err := client.ValidateOrgHook(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exists || !changed {
+		t.Fatalf("expected changed hook but got exists %v changed %v", exists, changed)
+	}
+
+	options.ID = "12"
+	if exists, _, err := client.ValidateOrgHook(options); err != nil || exists {
+		t.Fatalf("expected missing hook but got exists %v error %v", exists, err)
+	}
+}
+
+func TestGithubCheckRun(t *testing.T) {
+	requests := []map[string]interface{}{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/team/app/check-runs":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": 99}`)
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v3/repos/team/app/check-runs/99":
+			fmt.Fprint(w, `{"id": 99}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewGithubClient(server.URL, "token", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := &model.HookOptions{Owner: "team", Project: "app"}
+	status := &model.CommitStatus{SHA: "abc123", Branch: "master", State: model.CommitStateRunning, Context: "githook/app"}
+
+	checkRunID, err := client.SetCheckRun(options, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if checkRunID != "99" {
+		t.Fatalf("expected check run id 99 but got %s", checkRunID)
+	}
+
+	status.CheckRunID = checkRunID
+	status.State = model.CommitStateFailure
+
+	if _, err := client.SetCheckRun(options, status); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 || requests[0]["status"] != "in_progress" || requests[0]["head_sha"] != "abc123" {
+		t.Fatalf("unexpected create check run request %v", requests)
+	}
+
+	if requests[1]["status"] != "completed" || requests[1]["conclusion"] != "failure" {
+		t.Fatalf("unexpected update check run request %v", requests[1])
+	}
+}
+
+func TestGithubCommitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v3/repos/team/app/statuses/abc123" {
+			http.NotFound(w, r)
+			return
+		}
+
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if body["state"] != "error" || body["context"] != "githook/app" || body["target_url"] != "http://dashboard" {
+			t.Errorf("unexpected status %v", body)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client, err := NewGithubClient(server.URL, "token", http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.SetCommitStatus(&model.HookOptions{Owner: "team", Project: "app"}, &model.CommitStatus{
+		SHA:       "abc123",
+		State:     model.CommitStateCancelled,
+		Context:   "githook/app",
+		TargetURL: "http://dashboard",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
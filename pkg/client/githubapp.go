@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// github accepts app jwt valid for at most 10 minutes
+	githubAppJWTLifetime = 9 * time.Minute
+
+	// installation tokens are refreshed this long before they expire
+	githubTokenRefreshBefore = 5 * time.Minute
+)
+
+// GithubAppTokenCache keeps token sources of github app installations shared by clients
+// so installation tokens are reused until they expire
+type GithubAppTokenCache struct {
+	mutex   sync.Mutex
+	sources map[githubAppTokenKey]oauth2.TokenSource
+}
+
+// githubAppTokenKey identifies token source by installation, private key and CA bundle trusted when minting tokens
+type githubAppTokenKey struct {
+	baseURL        string
+	appID          int64
+	installationID int64
+	privateKey     [sha256.Size]byte
+	caBundle       [sha256.Size]byte
+}
+
+// NewGithubAppTokenCache creates empty cache of github app installation tokens
+func NewGithubAppTokenCache() *GithubAppTokenCache {
+	return &GithubAppTokenCache{sources: map[githubAppTokenKey]oauth2.TokenSource{}}
+}
+
+// Evict removes token sources of github app installation
+func (c *GithubAppTokenCache) Evict(baseURL string, appID, installationID int64) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.sources {
+		if key.baseURL == baseURL && key.appID == appID && key.installationID == installationID {
+			delete(c.sources, key)
+		}
+	}
+}
+
+// parseRSAPrivateKey parses PEM encoded PKCS1 or PKCS8 rsa private key
+func parseRSAPrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %s", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not a rsa key")
+	}
+
+	return rsaKey, nil
+}
+
+// githubAppJWT creates json web token authenticating as github app
+func githubAppJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		// backdate to allow clock drift
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(githubAppJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+
+	hashed := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + encoding.EncodeToString(signature), nil
+}
+
+// githubAppTransport authenticates requests as github app
+type githubAppTransport struct {
+	appID int64
+	key   *rsa.PrivateKey
+	base  http.RoundTripper
+}
+
+func (t *githubAppTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := githubAppJWT(t.appID, t.key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// requests must not be modified by round tripper
+	req2 := new(http.Request)
+	*req2 = *req
+	req2.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		req2.Header[k] = v
+	}
+	req2.Header.Set("Authorization", "Bearer "+token)
+
+	return t.base.RoundTrip(req2)
+}
+
+// githubInstallationTokenSource mints access tokens of github app installation
+type githubInstallationTokenSource struct {
+	baseURL        string
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	httpClient     *http.Client
+}
+
+// Token creates new installation token
+func (ts *githubInstallationTokenSource) Token() (*oauth2.Token, error) {
+	transport := ts.httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	appClient, err := newGithubAPIClient(ts.baseURL, &http.Client{
+		Transport: &githubAppTransport{appID: ts.appID, key: ts.key, base: transport},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	token, _, err := appClient.Apps.CreateInstallationToken(context.Background(), ts.installationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token of github app %d installation %d: %s", ts.appID, ts.installationID, err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		Expiry:      token.GetExpiresAt().Add(-githubTokenRefreshBefore),
+	}, nil
+}
+
+// tokenSource returns cached token source of github app installation. httpClient must trust caBundle.
+// Token sources are not cached by nil cache.
+func (c *GithubAppTokenCache) tokenSource(baseURL string, app *model.GithubAppOptions, caBundle string, httpClient *http.Client) (oauth2.TokenSource, error) {
+	cacheKey := githubAppTokenKey{
+		baseURL:        baseURL,
+		appID:          app.AppID,
+		installationID: app.InstallationID,
+		privateKey:     sha256.Sum256([]byte(app.PrivateKey)),
+		caBundle:       sha256.Sum256([]byte(caBundle)),
+	}
+
+	if c != nil {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		if ts, ok := c.sources[cacheKey]; ok {
+			return ts, nil
+		}
+	}
+
+	key, err := parseRSAPrivateKey(app.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := oauth2.ReuseTokenSource(nil, &githubInstallationTokenSource{
+		baseURL:        baseURL,
+		appID:          app.AppID,
+		installationID: app.InstallationID,
+		key:            key,
+		httpClient:     httpClient,
+	})
+
+	if c != nil {
+		c.sources[cacheKey] = ts
+	}
+
+	return ts, nil
+}
+
+// NewGithubAppClient creates new github git client authenticated as github app installation.
+// Installation tokens are cached in tokens and refreshed before they expire. httpClient must trust caBundle.
+func NewGithubAppClient(baseURL string, app *model.GithubAppOptions, caBundle string, httpClient *http.Client, tokens *GithubAppTokenCache) (*GithubClient, error) {
+	ts, err := tokens.tokenSource(baseURL, app, caBundle, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGithubClient(baseURL, ts, httpClient)
+}
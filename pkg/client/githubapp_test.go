@@ -0,0 +1,156 @@
+package client
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func verifyGithubAppJWT(t *testing.T, token string, key *rsa.PublicKey) map[string]interface{} {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected jwt but got %s", token)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashed := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Fatalf("invalid jwt signature: %s", err)
+	}
+
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(claims, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestGithubAppClient(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	tokensCreated := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/app/installations/42/access_tokens":
+			claims := verifyGithubAppJWT(t, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), &key.PublicKey)
+			if claims["iss"] != "7" {
+				t.Errorf("expected app id 7 as issuer but got %v", claims["iss"])
+			}
+
+			tokensCreated++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "installation-token", "expires_at": "%s"}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+		case "/api/v3/repos/team/app/pulls/1/files":
+			if r.Header.Get("Authorization") != "Bearer installation-token" {
+				t.Errorf("expected installation token but got %s", r.Header.Get("Authorization"))
+			}
+
+			fmt.Fprint(w, `[{"filename": "README.md"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	options := &model.HookOptions{Owner: "team", Project: "app"}
+	app := &model.GithubAppOptions{AppID: 7, InstallationID: 42, PrivateKey: privateKey}
+	tokens := NewGithubAppTokenCache()
+
+	listChangedFiles := func(caBundle string) {
+		client, err := NewGithubAppClient(server.URL, app, caBundle, http.DefaultClient, tokens)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files, err := client.ListChangedFiles(options, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(files) != 1 || files[0] != "README.md" {
+			t.Fatalf("unexpected changed files %v", files)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		caBundle      string
+		evict         bool
+		tokensCreated int
+	}{
+		{name: "first client creates token", tokensCreated: 1},
+		{name: "next client reuses token", tokensCreated: 1},
+		{name: "client trusting another CA bundle creates token", caBundle: "other", tokensCreated: 2},
+		{name: "client after eviction creates token", evict: true, tokensCreated: 3},
+	}
+
+	for _, test := range tests {
+		if test.evict {
+			tokens.Evict(server.URL, app.AppID, app.InstallationID)
+		}
+
+		listChangedFiles(test.caBundle)
+
+		if tokensCreated != test.tokensCreated {
+			t.Fatalf("%s: expected %d installation tokens to be created but got %d", test.name, test.tokensCreated, tokensCreated)
+		}
+	}
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, block := range []*pem.Block{
+		{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		{Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		parsed, err := parseRSAPrivateKey(string(pem.EncodeToMemory(block)))
+		if err != nil {
+			t.Fatalf("failed to parse %s: %s", block.Type, err)
+		}
+
+		if parsed.N.Cmp(key.N) != 0 {
+			t.Fatalf("unexpected key parsed from %s", block.Type)
+		}
+	}
+
+	if _, err := parseRSAPrivateKey("not a key"); err == nil {
+		t.Fatal("expected error for invalid private key")
+	}
+}
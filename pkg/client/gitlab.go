@@ -2,27 +2,39 @@ package client
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	gitlabclient "github.com/xanzy/go-gitlab"
 	"gitlab.com/pongsatt/githook/pkg/model"
 )
 
-// Event represents gitlab event type
+// Event represents gitlab hook attribute enabling an event
 type Event string
 
 const (
 	// PushEvents represents push event
-	PushEvents Event = "push"
+	PushEvents Event = "push_events"
+
+	// TagPushEvents represents tag_push event
+	TagPushEvents Event = "tag_push_events"
 
 	// IssuesEvents represents issues event
-	IssuesEvents Event = "issues"
+	IssuesEvents Event = "issues_events"
 
 	// CommentEvents represents issue_comment event
-	CommentEvents Event = "issue_comment"
+	CommentEvents Event = "note_events"
+
+	// MergeRequestEvents represents pull_request event
+	MergeRequestEvents Event = "merge_requests_events"
 
-	// MergeRequestEvents represents push pull_request
-	MergeRequestEvents Event = "pull_request"
+	// PipelineEvents represents pipeline event
+	PipelineEvents Event = "pipeline_events"
+
+	// WikiPageEvents represents wiki event
+	WikiPageEvents Event = "wiki_page_events"
 )
 
 // GitlabClient provides gitlab git client functionalities
@@ -33,10 +45,14 @@ type GitlabClient struct {
 func hookToEventList(hook *gitlabclient.ProjectHook) []Event {
 	events := make([]Event, 0)
 
-	if hook.PushEvents || hook.TagPushEvents {
+	if hook.PushEvents {
 		events = append(events, PushEvents)
 	}
 
+	if hook.TagPushEvents {
+		events = append(events, TagPushEvents)
+	}
+
 	if hook.IssuesEvents {
 		events = append(events, IssuesEvents)
 	}
@@ -49,52 +65,100 @@ func hookToEventList(hook *gitlabclient.ProjectHook) []Event {
 		events = append(events, CommentEvents)
 	}
 
+	if hook.PipelineEvents {
+		events = append(events, PipelineEvents)
+	}
+
+	if hook.WikiPageEvents {
+		events = append(events, WikiPageEvents)
+	}
+
 	return events
 }
 
-func eventListToAddHook(events []string, hook *gitlabclient.AddProjectHookOptions) {
-	trueValue := true
+// gitlabHookEvents keeps the attributes enabling events shared by add and edit hook options
+type gitlabHookEvents struct {
+	push, tagPush, issues, mergeRequests, note, pipeline, wikiPage *bool
+}
+
+// eventListToHookEvents enables hook attributes of events. Other attributes are disabled
+// so that updating a hook removes events no longer given.
+func eventListToHookEvents(events []string) (*gitlabHookEvents, error) {
+	hookEvents := &gitlabHookEvents{
+		push:          new(bool),
+		tagPush:       new(bool),
+		issues:        new(bool),
+		mergeRequests: new(bool),
+		note:          new(bool),
+		pipeline:      new(bool),
+		wikiPage:      new(bool),
+	}
 
 	for _, event := range events {
 		switch Event(event) {
 		case PushEvents:
-			hook.PushEvents = &trueValue
+			*hookEvents.push = true
+		case TagPushEvents:
+			*hookEvents.tagPush = true
 		case IssuesEvents:
-			hook.IssuesEvents = &trueValue
+			*hookEvents.issues = true
 		case MergeRequestEvents:
-			hook.MergeRequestsEvents = &trueValue
+			*hookEvents.mergeRequests = true
 		case CommentEvents:
-			hook.NoteEvents = &trueValue
+			*hookEvents.note = true
+		case PipelineEvents:
+			*hookEvents.pipeline = true
+		case WikiPageEvents:
+			*hookEvents.wikiPage = true
+		default:
+			return nil, fmt.Errorf("event %s is not supported by gitlab hook", event)
 		}
 	}
 
+	return hookEvents, nil
+}
+
+func eventListToAddHook(events []string, hook *gitlabclient.AddProjectHookOptions) error {
+	hookEvents, err := eventListToHookEvents(events)
+	if err != nil {
+		return err
+	}
+
+	hook.PushEvents = hookEvents.push
+	hook.TagPushEvents = hookEvents.tagPush
+	hook.IssuesEvents = hookEvents.issues
+	hook.MergeRequestsEvents = hookEvents.mergeRequests
+	hook.NoteEvents = hookEvents.note
+	hook.PipelineEvents = hookEvents.pipeline
+	hook.WikiPageEvents = hookEvents.wikiPage
+
+	return nil
 }
 
 func pid(options *model.HookOptions) string {
 	return fmt.Sprintf("%s/%s", options.Owner, options.Project)
 }
 
-func eventListToEditHook(events []string, hook *gitlabclient.EditProjectHookOptions) {
-	trueValue := true
-
-	for _, event := range events {
-		switch Event(event) {
-		case PushEvents:
-			hook.PushEvents = &trueValue
-		case IssuesEvents:
-			hook.IssuesEvents = &trueValue
-		case MergeRequestEvents:
-			hook.MergeRequestsEvents = &trueValue
-		case CommentEvents:
-			hook.NoteEvents = &trueValue
-		}
+func eventListToEditHook(events []string, hook *gitlabclient.EditProjectHookOptions) error {
+	hookEvents, err := eventListToHookEvents(events)
+	if err != nil {
+		return err
 	}
 
+	hook.PushEvents = hookEvents.push
+	hook.TagPushEvents = hookEvents.tagPush
+	hook.IssuesEvents = hookEvents.issues
+	hook.MergeRequestsEvents = hookEvents.mergeRequests
+	hook.NoteEvents = hookEvents.note
+	hook.PipelineEvents = hookEvents.pipeline
+	hook.WikiPageEvents = hookEvents.wikiPage
+
+	return nil
 }
 
 // NewGitlabClient creates new gitlab git client
-func NewGitlabClient(baseURL, accessToken string) *GitlabClient {
-	gitlabClient := gitlabclient.NewClient(nil, accessToken)
+func NewGitlabClient(baseURL, accessToken string, httpClient *http.Client) *GitlabClient {
+	gitlabClient := gitlabclient.NewClient(httpClient, accessToken)
 	gitlabClient.SetBaseURL(baseURL)
 
 	return &GitlabClient{
@@ -118,13 +182,18 @@ func (client *GitlabClient) Validate(options *model.HookOptions) (exists bool, c
 		return false, false, nil
 	}
 
+	return true, gitlabHookChanged(hook, options), nil
+}
+
+// gitlabHookChanged checks if url or events of hook differ from options
+func gitlabHookChanged(hook *gitlabclient.ProjectHook, options *model.HookOptions) bool {
 	if hook.URL != options.URL {
-		return true, true, nil
+		return true
 	}
 
 	events := hookToEventList(hook)
 	if len(events) != len(options.Events) {
-		return true, true, nil
+		return true
 	}
 
 	eventSet := make(map[string]bool)
@@ -135,11 +204,11 @@ func (client *GitlabClient) Validate(options *model.HookOptions) (exists bool, c
 
 	for _, event := range options.Events {
 		if eventSet[event] == false {
-			return true, true, nil
+			return true
 		}
 	}
 
-	return true, false, nil
+	return false
 }
 
 func (client *GitlabClient) getHook(options *model.HookOptions) (*gitlabclient.ProjectHook, error) {
@@ -165,7 +234,9 @@ func (client *GitlabClient) Create(options *model.HookOptions) (string, error) {
 		Token: &options.SecretToken,
 	}
 
-	eventListToAddHook(options.Events, hookOptions)
+	if err := eventListToAddHook(options.Events, hookOptions); err != nil {
+		return "", err
+	}
 
 	hook, _, err := client.gitlabClient.Projects.AddProjectHook(pid(options), hookOptions)
 	if err != nil {
@@ -192,7 +263,9 @@ func (client *GitlabClient) Update(options *model.HookOptions) (string, error) {
 		Token: &options.SecretToken,
 	}
 
-	eventListToEditHook(options.Events, hookOptions)
+	if err := eventListToEditHook(options.Events, hookOptions); err != nil {
+		return "", err
+	}
 
 	hookID, err := strconv.Atoi(options.ID)
 
@@ -225,3 +298,150 @@ func (client *GitlabClient) Delete(options *model.HookOptions) error {
 
 	return nil
 }
+
+// ListChangedFiles lists files changed by merge request
+func (client *GitlabClient) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	mergeRequest, _, err := client.gitlabClient.MergeRequests.GetMergeRequestChanges(pid(options), number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changes of merge request %d of project '%s' : %s", number, options.Project, err)
+	}
+
+	files := make([]string, 0)
+	for _, change := range mergeRequest.Changes {
+		files = append(files, change.NewPath)
+		if change.RenamedFile {
+			files = append(files, change.OldPath)
+		}
+	}
+
+	return files, nil
+}
+
+// gitlabGroupHooksPath returns api path of group hooks. Group hooks share the
+// attributes of project hooks but are not provided by the gitlab client library.
+func gitlabGroupHooksPath(options *model.HookOptions) string {
+	// group path is escaped as single path segment same as the gitlab client library
+	group := strings.Replace(url.PathEscape(options.Owner), ".", "%2E", -1)
+	return fmt.Sprintf("groups/%s/hooks", group)
+}
+
+func (client *GitlabClient) groupHookRequest(method, path string, opt interface{}, hook *gitlabclient.ProjectHook) (*gitlabclient.Response, error) {
+	req, err := client.gitlabClient.NewRequest(method, path, opt, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if hook == nil {
+		return client.gitlabClient.Do(req, nil)
+	}
+	return client.gitlabClient.Do(req, hook)
+}
+
+// ValidateOrgHook checks if group hook has been changed
+func (client *GitlabClient) ValidateOrgHook(options *model.HookOptions) (exists bool, changed bool, err error) {
+	if options.ID == "" {
+		return false, false, nil
+	}
+
+	hook := &gitlabclient.ProjectHook{}
+	resp, err := client.groupHookRequest(http.MethodGet, gitlabGroupHooksPath(options)+"/"+url.PathEscape(options.ID), nil, hook)
+
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("failed to get webhook of group '%s' : %s", options.Owner, err)
+	}
+
+	return true, gitlabHookChanged(hook, options), nil
+}
+
+// CreateOrgHook creates group webhook
+func (client *GitlabClient) CreateOrgHook(options *model.HookOptions) (string, error) {
+	hookOptions := &gitlabclient.AddProjectHookOptions{
+		URL:   &options.URL,
+		Token: &options.SecretToken,
+	}
+
+	if err := eventListToAddHook(options.Events, hookOptions); err != nil {
+		return "", err
+	}
+
+	hook := &gitlabclient.ProjectHook{}
+	if _, err := client.groupHookRequest(http.MethodPost, gitlabGroupHooksPath(options), hookOptions, hook); err != nil {
+		return "", fmt.Errorf("failed to add webhook to group '%s' : %s", options.Owner, err)
+	}
+
+	return strconv.Itoa(hook.ID), nil
+}
+
+// UpdateOrgHook updates group webhook
+func (client *GitlabClient) UpdateOrgHook(options *model.HookOptions) (string, error) {
+	if options.ID == "" {
+		return "", fmt.Errorf("webhook id is required to be updated")
+	}
+
+	hookOptions := &gitlabclient.EditProjectHookOptions{
+		URL:   &options.URL,
+		Token: &options.SecretToken,
+	}
+
+	if err := eventListToEditHook(options.Events, hookOptions); err != nil {
+		return "", err
+	}
+
+	hook := &gitlabclient.ProjectHook{}
+	if _, err := client.groupHookRequest(http.MethodPut, gitlabGroupHooksPath(options)+"/"+url.PathEscape(options.ID), hookOptions, hook); err != nil {
+		return "", fmt.Errorf("failed to update webhook of group '%s' : %s", options.Owner, err)
+	}
+
+	return strconv.Itoa(hook.ID), nil
+}
+
+// DeleteOrgHook deletes group webhook
+func (client *GitlabClient) DeleteOrgHook(options *model.HookOptions) error {
+	if options.ID != "" {
+		resp, err := client.groupHookRequest(http.MethodDelete, gitlabGroupHooksPath(options)+"/"+url.PathEscape(options.ID), nil, nil)
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return fmt.Errorf("failed to delete hook of group '%s' : %s", options.Owner, err)
+		}
+	}
+
+	return nil
+}
+
+// gitlabBuildState maps state of pipeline run to gitlab commit status
+func gitlabBuildState(state model.CommitState) gitlabclient.BuildStateValue {
+	switch state {
+	case model.CommitStateRunning:
+		return gitlabclient.Running
+	case model.CommitStateSuccess:
+		return gitlabclient.Success
+	case model.CommitStateFailure:
+		return gitlabclient.Failed
+	case model.CommitStateCancelled:
+		return gitlabclient.Canceled
+	}
+
+	return gitlabclient.Pending
+}
+
+// SetCommitStatus reports status of pipeline run on commit
+func (client *GitlabClient) SetCommitStatus(options *model.HookOptions, status *model.CommitStatus) error {
+	statusOptions := &gitlabclient.SetCommitStatusOptions{
+		State:       gitlabBuildState(status.State),
+		Name:        &status.Context,
+		Description: &status.Description,
+	}
+
+	if status.TargetURL != "" {
+		statusOptions.TargetURL = &status.TargetURL
+	}
+
+	_, _, err := client.gitlabClient.Commits.SetCommitStatus(pid(options), status.SHA, statusOptions)
+	if err != nil {
+		return fmt.Errorf("failed to set status of commit %s of project '%s' : %s", status.SHA, options.Project, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gitlabclient "github.com/xanzy/go-gitlab"
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func TestGitlabGroupHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/api/v4/groups/mygroup%2Fsub/hooks":
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			if body["url"] != "http://hook.example.com" || body["push_events"] != true || body["token"] != "secret" {
+				t.Errorf("unexpected hook %v", body)
+			}
+
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 5, "url": body["url"], "push_events": true})
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/api/v4/groups/mygroup%2Fsub/hooks/5":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 5, "url": "http://hook.example.com", "push_events": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGitlabClient(server.URL, "token", http.DefaultClient)
+	options := &model.HookOptions{
+		Owner:        "mygroup/sub",
+		Organization: true,
+		URL:          "http://hook.example.com",
+		SecretToken:  "secret",
+		Events:       []string{"push_events"},
+	}
+
+	hookID, err := client.CreateOrgHook(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hookID != "5" {
+		t.Fatalf("expected hook id 5 but got %s", hookID)
+	}
+
+	options.ID = hookID
+	exists, changed, err := client.ValidateOrgHook(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exists || changed {
+		t.Fatalf("expected unchanged hook but got exists %v changed %v", exists, changed)
+	}
+
+	options.ID = "6"
+	if exists, _, err := client.ValidateOrgHook(options); err != nil || exists {
+		t.Fatalf("expected missing hook but got exists %v error %v", exists, err)
+	}
+}
+
+func TestGitlabCommitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.EscapedPath() != "/api/v4/projects/mygroup%2Fapp/statuses/abc123" {
+			http.NotFound(w, r)
+			return
+		}
+
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if body["state"] != "failed" || body["name"] != "githook/app" {
+			t.Errorf("unexpected status %v", body)
+		}
+
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := NewGitlabClient(server.URL, "token", http.DefaultClient)
+
+	err := client.SetCommitStatus(&model.HookOptions{Owner: "mygroup", Project: "app"}, &model.CommitStatus{
+		SHA:     "abc123",
+		State:   model.CommitStateFailure,
+		Context: "githook/app",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGitlabHookEvents(t *testing.T) {
+	hook := &gitlabclient.ProjectHook{URL: "http://hook.example.com", PushEvents: true}
+
+	tests := []struct {
+		events  []string
+		changed bool
+	}{
+		{events: []string{"push_events"}, changed: false},
+		{events: []string{"push_events", "tag_push_events"}, changed: true},
+		{events: []string{"tag_push_events"}, changed: true},
+	}
+
+	for _, test := range tests {
+		options := &model.HookOptions{URL: "http://hook.example.com", Events: test.events}
+		if changed := gitlabHookChanged(hook, options); changed != test.changed {
+			t.Fatalf("expected changed %v of events %v but got %v", test.changed, test.events, changed)
+		}
+	}
+
+	editOptions := &gitlabclient.EditProjectHookOptions{}
+	if err := eventListToEditHook([]string{"tag_push_events", "pipeline_events"}, editOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	if *editOptions.PushEvents || !*editOptions.TagPushEvents || !*editOptions.PipelineEvents {
+		t.Fatalf("expected tag push and pipeline events only but got %+v", editOptions)
+	}
+
+	if err := eventListToAddHook([]string{"release"}, &gitlabclient.AddProjectHookOptions{}); err == nil {
+		t.Fatalf("expected error of event not supported by gitlab")
+	}
+}
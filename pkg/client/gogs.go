@@ -2,6 +2,7 @@ package client
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
 
 	gogs "github.com/gogits/go-gogs-client"
@@ -14,8 +15,9 @@ type GogsClient struct {
 }
 
 // NewGogsClient creates new gogs git client
-func NewGogsClient(baseURL, accessToken string) *GogsClient {
+func NewGogsClient(baseURL, accessToken string, httpClient *http.Client) *GogsClient {
 	gogsClient := gogs.NewClient(baseURL, accessToken)
+	gogsClient.SetHTTPClient(httpClient)
 
 	return &GogsClient{
 		gogsClient,
@@ -153,3 +155,8 @@ func (client *GogsClient) Delete(options *model.HookOptions) error {
 
 	return nil
 }
+
+// ListChangedFiles is not supported since gogs api does not provide pull request files
+func (client *GogsClient) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	return nil, model.ErrNotSupported
+}
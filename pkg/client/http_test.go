@@ -0,0 +1,47 @@
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewHTTPClientWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	httpClient, err := NewHTTPClient(string(caBundle))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := httpClient.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected server certificate to be trusted but got %s", err)
+	}
+	resp.Body.Close()
+
+	if _, err := http.DefaultClient.Get(server.URL); err == nil {
+		t.Fatal("expected default client not to trust server certificate")
+	}
+}
+
+func TestNewHTTPClientWithoutCABundle(t *testing.T) {
+	httpClient, err := NewHTTPClient("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if httpClient != http.DefaultClient {
+		t.Fatal("expected default client")
+	}
+
+	if _, err := NewHTTPClient("not a certificate"); err == nil {
+		t.Fatal("expected error for invalid CA bundle")
+	}
+}
@@ -17,6 +17,29 @@ type GitClient interface {
 	Create(options *model.HookOptions) (string, error)
 	Update(options *model.HookOptions) (string, error)
 	Delete(options *model.HookOptions) error
+	ListChangedFiles(options *model.HookOptions, number int) ([]string, error)
+}
+
+// OrgHookClient is implemented by git clients supporting organization (or group) webhooks
+// registered on options.Owner
+type OrgHookClient interface {
+	ValidateOrgHook(options *model.HookOptions) (exists bool, changed bool, err error)
+	CreateOrgHook(options *model.HookOptions) (string, error)
+	UpdateOrgHook(options *model.HookOptions) (string, error)
+	DeleteOrgHook(options *model.HookOptions) error
+}
+
+// CommitStatusClient is implemented by git clients supporting commit statuses
+type CommitStatusClient interface {
+	// SetCommitStatus reports status of pipeline run on commit of project
+	SetCommitStatus(options *model.HookOptions, status *model.CommitStatus) error
+}
+
+// CheckRunClient is implemented by git clients supporting check runs
+type CheckRunClient interface {
+	// SetCheckRun creates or updates check run of pipeline run on commit of project.
+	// It returns id of the check run.
+	SetCheckRun(options *model.HookOptions, status *model.CommitStatus) (string, error)
 }
 
 // Client provides webhook client
@@ -31,22 +54,85 @@ func New(gitClient GitClient, baseURL, accessToken string) (*Client, error) {
 	}, nil
 }
 
-// Create creates webhook
+// orgHookClient returns git client as OrgHookClient or ErrNotSupported if organization webhooks are not supported
+func (client Client) orgHookClient() (OrgHookClient, error) {
+	orgHookClient, ok := client.GitClient.(OrgHookClient)
+	if !ok {
+		return nil, model.ErrNotSupported
+	}
+
+	return orgHookClient, nil
+}
+
+// Create creates webhook on project or organization
 func (client Client) Create(options *model.HookOptions) (string, error) {
+	if options.Organization {
+		orgHookClient, err := client.orgHookClient()
+		if err != nil {
+			return "", err
+		}
+		return orgHookClient.CreateOrgHook(options)
+	}
 	return client.GitClient.Create(options)
 }
 
-// Update updates webhook
+// Update updates webhook on project or organization
 func (client Client) Update(options *model.HookOptions) (string, error) {
+	if options.Organization {
+		orgHookClient, err := client.orgHookClient()
+		if err != nil {
+			return "", err
+		}
+		return orgHookClient.UpdateOrgHook(options)
+	}
 	return client.GitClient.Update(options)
 }
 
 // Validate checks if hook has been changed
 func (client Client) Validate(options *model.HookOptions) (exists bool, changed bool, err error) {
+	if options.Organization {
+		orgHookClient, err := client.orgHookClient()
+		if err != nil {
+			return false, false, err
+		}
+		return orgHookClient.ValidateOrgHook(options)
+	}
 	return client.GitClient.Validate(options)
 }
 
 // Delete webhook
 func (client Client) Delete(options *model.HookOptions) error {
+	if options.Organization {
+		orgHookClient, err := client.orgHookClient()
+		if err != nil {
+			return err
+		}
+		return orgHookClient.DeleteOrgHook(options)
+	}
 	return client.GitClient.Delete(options)
 }
+
+// ListChangedFiles lists files changed by pull request or merge request
+func (client Client) ListChangedFiles(options *model.HookOptions, number int) ([]string, error) {
+	return client.GitClient.ListChangedFiles(options, number)
+}
+
+// SetCommitStatus reports status of pipeline run on commit or returns ErrNotSupported
+// if commit statuses are not supported
+func (client Client) SetCommitStatus(options *model.HookOptions, status *model.CommitStatus) error {
+	commitStatusClient, ok := client.GitClient.(CommitStatusClient)
+	if !ok {
+		return model.ErrNotSupported
+	}
+	return commitStatusClient.SetCommitStatus(options, status)
+}
+
+// SetCheckRun creates or updates check run of pipeline run on commit or returns ErrNotSupported
+// if check runs are not supported
+func (client Client) SetCheckRun(options *model.HookOptions, status *model.CommitStatus) (string, error) {
+	checkRunClient, ok := client.GitClient.(CheckRunClient)
+	if !ok {
+		return "", model.ErrNotSupported
+	}
+	return checkRunClient.SetCheckRun(options, status)
+}
@@ -0,0 +1,82 @@
+package githook
+
+import (
+	"testing"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func TestOrgHookSupport(t *testing.T) {
+	tests := []struct {
+		gitProvider v1alpha1.GitProvider
+		supported   bool
+	}{
+		{gitProvider: v1alpha1.Github, supported: true},
+		{gitProvider: v1alpha1.Gitlab, supported: true},
+		{gitProvider: v1alpha1.Gitea, supported: true},
+		{gitProvider: v1alpha1.Gogs},
+		{gitProvider: v1alpha1.Bitbucket},
+		{gitProvider: v1alpha1.BitbucketServer},
+		{gitProvider: v1alpha1.AzureDevOps},
+	}
+
+	for _, test := range tests {
+		options := &model.HookOptions{BaseURL: "https://git.example.com", Owner: "team", AccessToken: "token", Organization: true}
+
+		client, err := NewForProvider(test.gitProvider, options, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %s", test.gitProvider, err)
+		}
+
+		_, supported := client.GitClient.(OrgHookClient)
+		if supported != test.supported {
+			t.Fatalf("%s: expected organization webhooks supported %t but got %t", test.gitProvider, test.supported, supported)
+		}
+
+		if !supported {
+			if _, err := client.Create(options); err != model.ErrNotSupported {
+				t.Fatalf("%s: expected %s but got %v", test.gitProvider, model.ErrNotSupported, err)
+			}
+		}
+	}
+}
+
+func TestCommitStatusSupport(t *testing.T) {
+	tests := []struct {
+		gitProvider  v1alpha1.GitProvider
+		commitStatus bool
+		checkRun     bool
+	}{
+		{gitProvider: v1alpha1.Github, commitStatus: true, checkRun: true},
+		{gitProvider: v1alpha1.Gitlab, commitStatus: true},
+		{gitProvider: v1alpha1.Gitea, commitStatus: true},
+		{gitProvider: v1alpha1.Gogs},
+		{gitProvider: v1alpha1.Bitbucket},
+		{gitProvider: v1alpha1.BitbucketServer},
+		{gitProvider: v1alpha1.AzureDevOps},
+	}
+
+	for _, test := range tests {
+		options := &model.HookOptions{BaseURL: "https://git.example.com", Owner: "team", AccessToken: "token"}
+
+		client, err := NewForProvider(test.gitProvider, options, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %s", test.gitProvider, err)
+		}
+
+		if _, commitStatus := client.GitClient.(CommitStatusClient); commitStatus != test.commitStatus {
+			t.Fatalf("%s: expected commit status supported %t but got %t", test.gitProvider, test.commitStatus, commitStatus)
+		}
+
+		if _, checkRun := client.GitClient.(CheckRunClient); checkRun != test.checkRun {
+			t.Fatalf("%s: expected check run supported %t but got %t", test.gitProvider, test.checkRun, checkRun)
+		}
+
+		if !test.checkRun {
+			if _, err := client.SetCheckRun(options, &model.CommitStatus{}); err != model.ErrNotSupported {
+				t.Fatalf("%s: expected %s but got %v", test.gitProvider, model.ErrNotSupported, err)
+			}
+		}
+	}
+}
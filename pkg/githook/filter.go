@@ -0,0 +1,174 @@
+package githook
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+// globToRegexp converts glob pattern to regular expression.
+// * matches any character except / and ** matches any character.
+func globToRegexp(glob string) string {
+	var builder strings.Builder
+
+	builder.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				builder.WriteString(".*")
+				i++
+			} else {
+				builder.WriteString("[^/]*")
+			}
+		case '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+	builder.WriteString("$")
+
+	return builder.String()
+}
+
+// CompilePattern compiles pattern of filters and routes. Pattern enclosed in slashes is
+// a regular expression otherwise it is a glob.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	expr := globToRegexp(pattern)
+
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		expr = pattern[1 : len(pattern)-1]
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+	}
+
+	return re, nil
+}
+
+// matchPattern checks if name matches the pattern
+func matchPattern(pattern, name string) (bool, error) {
+	re, err := CompilePattern(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(name), nil
+}
+
+// matchPatterns returns the first pattern matches name or empty if none
+func matchPatterns(patterns []string, name string) (string, error) {
+	for _, pattern := range patterns {
+		matched, err := matchPattern(pattern, name)
+		if err != nil {
+			return "", err
+		}
+
+		if matched {
+			return pattern, nil
+		}
+	}
+
+	return "", nil
+}
+
+func filterName(kind, name string, includes, excludes []string) (string, error) {
+	if len(includes) > 0 {
+		matched, err := matchPatterns(includes, name)
+		if err != nil {
+			return "", err
+		}
+
+		if matched == "" {
+			return fmt.Sprintf("%s %q does not match any of %s filters", kind, name, kind), nil
+		}
+	}
+
+	matched, err := matchPatterns(excludes, name)
+	if err != nil {
+		return "", err
+	}
+
+	if matched != "" {
+		return fmt.Sprintf("%s %q is ignored by pattern %q", kind, name, matched), nil
+	}
+
+	return "", nil
+}
+
+// filterEvent returns the reason why the event should be skipped or empty if the event should trigger
+func filterEvent(filters *v1alpha1.GitHookFilters, options tekton.PipelineOptions) (string, error) {
+	if filters == nil {
+		return "", nil
+	}
+
+	hasBranchFilters := len(filters.Branches) > 0 || len(filters.BranchesIgnore) > 0
+	hasTagFilters := len(filters.Tags) > 0 || len(filters.TagsIgnore) > 0
+
+	if options.GitTag != "" {
+		if !hasTagFilters {
+			if hasBranchFilters {
+				return fmt.Sprintf("tag %q is skipped because only branch filters are configured", options.GitTag), nil
+			}
+			return "", nil
+		}
+
+		return filterName("tag", options.GitTag, filters.Tags, filters.TagsIgnore)
+	}
+
+	if options.GitBranch != "" {
+		if !hasBranchFilters {
+			if hasTagFilters {
+				return fmt.Sprintf("branch %q is skipped because only tag filters are configured", options.GitBranch), nil
+			}
+			return "", nil
+		}
+
+		return filterName("branch", options.GitBranch, filters.Branches, filters.BranchesIgnore)
+	}
+
+	return "", nil
+}
+
+// hasPathFilters checks if any path filter is configured
+func hasPathFilters(filters *v1alpha1.GitHookFilters) bool {
+	return filters != nil && (len(filters.Paths) > 0 || len(filters.PathsIgnore) > 0)
+}
+
+// filterPaths returns the reason why the event should be skipped or empty if any changed file is relevant.
+// Path filters are not applied when changed files are unknown.
+func filterPaths(filters *v1alpha1.GitHookFilters, files []string) (string, error) {
+	if !hasPathFilters(filters) || files == nil {
+		return "", nil
+	}
+
+	for _, file := range files {
+		if len(filters.Paths) > 0 {
+			matched, err := matchPatterns(filters.Paths, file)
+			if err != nil {
+				return "", err
+			}
+
+			if matched == "" {
+				continue
+			}
+		}
+
+		ignored, err := matchPatterns(filters.PathsIgnore, file)
+		if err != nil {
+			return "", err
+		}
+
+		if ignored == "" {
+			return "", nil
+		}
+	}
+
+	return fmt.Sprintf("none of %d changed files matches path filters", len(files)), nil
+}
@@ -0,0 +1,174 @@
+package githook
+
+import (
+	"testing"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+func TestMatchPattern(t *testing.T) {
+	testcases := []struct {
+		pattern string
+		name    string
+		matched bool
+	}{
+		{pattern: "master", name: "master", matched: true},
+		{pattern: "master", name: "master2", matched: false},
+		{pattern: "feature/*", name: "feature/login", matched: true},
+		{pattern: "feature/*", name: "feature/login/api", matched: false},
+		{pattern: "feature/**", name: "feature/login/api", matched: true},
+		{pattern: "v?.0", name: "v1.0", matched: true},
+		{pattern: "v?.0", name: "v1x0", matched: false},
+		{pattern: "/^release-[0-9]+$/", name: "release-12", matched: true},
+		{pattern: "/^release-[0-9]+$/", name: "release-x", matched: false},
+	}
+
+	for _, testcase := range testcases {
+		matched, err := matchPattern(testcase.pattern, testcase.name)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if matched != testcase.matched {
+			t.Fatalf("expected pattern %s matching %s to be %v", testcase.pattern, testcase.name, testcase.matched)
+		}
+	}
+}
+
+func TestMatchPatternInvalidRegexp(t *testing.T) {
+	_, err := matchPattern("/[/", "master")
+
+	if err == nil {
+		t.Fatalf("expected error for invalid regular expression")
+	}
+}
+
+func TestFilterEvent(t *testing.T) {
+	testcases := []struct {
+		filters *v1alpha1.GitHookFilters
+		branch  string
+		tag     string
+		skipped bool
+	}{
+		{
+			filters: nil,
+			branch:  "feature/a",
+			skipped: false,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Branches: []string{"master", "release/*"}},
+			branch:  "release/1.0",
+			skipped: false,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Branches: []string{"master", "release/*"}},
+			branch:  "feature/a",
+			skipped: true,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{BranchesIgnore: []string{"feature/**"}},
+			branch:  "feature/a/b",
+			skipped: true,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Branches: []string{"**"}, BranchesIgnore: []string{"feature/*"}},
+			branch:  "master",
+			skipped: false,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Branches: []string{"master"}},
+			tag:     "v1.0",
+			skipped: true,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Tags: []string{"v*"}},
+			tag:     "v1.0",
+			skipped: false,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Tags: []string{"v*"}, TagsIgnore: []string{"/-rc[0-9]*$/"}},
+			tag:     "v1.0-rc1",
+			skipped: true,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Tags: []string{"v*"}},
+			branch:  "master",
+			skipped: true,
+		},
+	}
+
+	for i, testcase := range testcases {
+		options := tekton.PipelineOptions{
+			GitBranch: testcase.branch,
+			GitTag:    testcase.tag,
+		}
+
+		reason, err := filterEvent(testcase.filters, options)
+
+		if err != nil {
+			t.Fatalf("case %d: unexpected error: %s", i, err)
+		}
+
+		if (reason != "") != testcase.skipped {
+			t.Fatalf("case %d: expected skipped to be %v but got reason %q", i, testcase.skipped, reason)
+		}
+	}
+}
+
+func TestFilterPaths(t *testing.T) {
+	testcases := []struct {
+		filters *v1alpha1.GitHookFilters
+		files   []string
+		skipped bool
+	}{
+		{
+			filters: &v1alpha1.GitHookFilters{Paths: []string{"services/api/**"}},
+			files:   nil,
+			skipped: false,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Paths: []string{"services/api/**"}},
+			files:   []string{"services/web/main.go", "services/api/cmd/main.go"},
+			skipped: false,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Paths: []string{"services/api/**"}},
+			files:   []string{"services/web/main.go"},
+			skipped: true,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Paths: []string{"services/api/**"}},
+			files:   []string{},
+			skipped: true,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{PathsIgnore: []string{"**.md", "docs/**"}},
+			files:   []string{"README.md", "docs/index.html"},
+			skipped: true,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{PathsIgnore: []string{"**.md"}},
+			files:   []string{"README.md", "main.go"},
+			skipped: false,
+		},
+		{
+			filters: &v1alpha1.GitHookFilters{Paths: []string{"services/api/**"}, PathsIgnore: []string{"**.md"}},
+			files:   []string{"services/api/README.md", "services/web/main.go"},
+			skipped: true,
+		},
+	}
+
+	for i, testcase := range testcases {
+		reason, err := filterPaths(testcase.filters, testcase.files)
+
+		if err != nil {
+			t.Fatalf("case %d: unexpected error: %s", i, err)
+		}
+
+		if (reason != "") != testcase.skipped {
+			t.Fatalf("case %d: expected skipped to be %v but got reason %q", i, testcase.skipped, reason)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package githook
+
+import (
+	"fmt"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/client"
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+// NewForProvider creates client of git provider with base url, credentials and CA bundle of options.
+// Installation tokens of github app are cached in tokens.
+func NewForProvider(gitProvider v1alpha1.GitProvider, options *model.HookOptions, tokens *client.GithubAppTokenCache) (*Client, error) {
+	httpClient, err := client.NewHTTPClient(options.CABundle)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid CA bundle: %s", err)
+	}
+
+	if options.GithubApp != nil && gitProvider != v1alpha1.Github {
+		return nil, fmt.Errorf("github app is not supported by git provider %s", gitProvider)
+	}
+
+	var gitClient GitClient
+
+	switch gitProvider {
+	case v1alpha1.Gogs:
+		gitClient = client.NewGogsClient(options.BaseURL, options.AccessToken, httpClient)
+	case v1alpha1.Github:
+		if app := options.GithubApp; app != nil {
+			gitClient, err = client.NewGithubAppClient(options.BaseURL, app, options.CABundle, httpClient, tokens)
+		} else {
+			gitClient, err = client.NewGithubClient(options.BaseURL, options.AccessToken, httpClient)
+		}
+	case v1alpha1.Gitlab:
+		gitClient = client.NewGitlabClient(options.BaseURL, options.AccessToken, httpClient)
+	case v1alpha1.Gitea:
+		gitClient = client.NewGiteaClient(options.BaseURL, options.AccessToken, httpClient)
+	case v1alpha1.Bitbucket:
+		gitClient = client.NewBitbucketClient(options.AccessToken)
+	case v1alpha1.BitbucketServer:
+		gitClient = client.NewBitbucketServerClient(options.BaseURL, options.AccessToken, httpClient)
+	case v1alpha1.AzureDevOps:
+		gitClient = client.NewAzureDevOpsClient(options.BaseURL, options.AccessToken, httpClient)
+	default:
+		return nil, fmt.Errorf("git provider %s not supported", gitProvider)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return New(gitClient, options.BaseURL, options.AccessToken)
+}
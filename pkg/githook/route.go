@@ -0,0 +1,46 @@
+package githook
+
+import (
+	"encoding/json"
+
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+// runSpecJSONFor returns pipelinerun spec in json format of the first route matching
+// repository name or the default one if none matches
+func (ra *ReceiveAdapter) runSpecJSONFor(repoName string) (string, error) {
+	for _, route := range ra.Routes {
+		matched, err := matchPatterns(route.Repositories, repoName)
+		if err != nil {
+			return "", err
+		}
+
+		if matched != "" {
+			if route.PipelineRunSpec != nil {
+				return string(route.PipelineRunSpec.Raw), nil
+			}
+
+			runSpecJSON, err := json.Marshal(route.RunSpec)
+			if err != nil {
+				return "", err
+			}
+			return string(runSpecJSON), nil
+		}
+	}
+
+	return ra.RunSpecJSON, nil
+}
+
+// repoHookOptions returns options to query the repository of the event. Organization
+// webhooks deliver events of many repositories so the repository is taken from the event.
+func (ra *ReceiveAdapter) repoHookOptions(repoOwner, repoName string) *model.HookOptions {
+	if !ra.HookOptions.Organization || repoName == "" {
+		return ra.HookOptions
+	}
+
+	options := *ra.HookOptions
+	options.Owner = repoOwner
+	options.Project = repoName
+
+	return &options
+}
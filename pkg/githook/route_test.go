@@ -0,0 +1,70 @@
+package githook
+
+import (
+	"strings"
+	"testing"
+
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/model"
+)
+
+func TestRunSpecJSONFor(t *testing.T) {
+	ra := &ReceiveAdapter{
+		RunSpecJSON: `{"pipelineRef":{"name":"default"}}`,
+		Routes: []v1alpha1.RepositoryRoute{
+			{
+				Repositories: []string{"api-*", "gateway"},
+				RunSpec:      tektonv1alpha1.PipelineRunSpec{PipelineRef: tektonv1alpha1.PipelineRef{Name: "go-build"}},
+			},
+			{
+				Repositories: []string{"/^web-.*$/"},
+				RunSpec:      tektonv1alpha1.PipelineRunSpec{PipelineRef: tektonv1alpha1.PipelineRef{Name: "node-build"}},
+			},
+		},
+	}
+
+	testcases := []struct {
+		repoName string
+		pipeline string
+	}{
+		{repoName: "api-users", pipeline: "go-build"},
+		{repoName: "gateway", pipeline: "go-build"},
+		{repoName: "web-shop", pipeline: "node-build"},
+		{repoName: "docs", pipeline: "default"},
+	}
+
+	for _, testcase := range testcases {
+		runSpecJSON, err := ra.runSpecJSONFor(testcase.repoName)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !strings.Contains(runSpecJSON, `"name":"`+testcase.pipeline+`"`) {
+			t.Fatalf("expected pipeline %s for repository %s but got %s", testcase.pipeline, testcase.repoName, runSpecJSON)
+		}
+	}
+}
+
+func TestRepoHookOptions(t *testing.T) {
+	ra := &ReceiveAdapter{
+		HookOptions: &model.HookOptions{Owner: "myorg", Organization: true},
+	}
+
+	options := ra.repoHookOptions("myorg", "api")
+
+	if options.Owner != "myorg" || options.Project != "api" {
+		t.Fatalf("expected repository of the event but got %s/%s", options.Owner, options.Project)
+	}
+
+	if ra.HookOptions.Project != "" {
+		t.Fatal("expected hook options not to be modified")
+	}
+
+	ra.HookOptions = &model.HookOptions{Owner: "team", Project: "app"}
+
+	if options := ra.repoHookOptions("someone", "fork"); options.Project != "app" {
+		t.Fatalf("expected project of project webhook but got %s", options.Project)
+	}
+}
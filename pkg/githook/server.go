@@ -1,16 +1,23 @@
 package githook
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"strings"
 
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/model"
 	"gitlab.com/pongsatt/githook/pkg/tekton"
 )
 
 // HookServer provides git provider specific functionality
 type HookServer interface {
 	GetEventHeader() string
+	GetDeliveryHeader() string
 	Parse(r *http.Request) (interface{}, error)
 	BuildOptionFromPayload(payload interface{}) tekton.PipelineOptions
 }
@@ -20,52 +27,253 @@ type HookServer interface {
 type ReceiveAdapter struct {
 	TektonClient *tekton.Client
 
+	// GitClient and HookOptions are used to query the git provider
+	// for information not included in the payload
+	GitClient   *Client
+	HookOptions *model.HookOptions
+
 	HookServer  HookServer
 	Namespace   string
 	Name        string
 	RunSpecJSON string
+	Routes      []v1alpha1.RepositoryRoute
+	RenderMode  v1alpha1.RenderMode
+	Params      []v1alpha1.ParamMapping
+	Filters     *v1alpha1.GitHookFilters
+	Triggers    []v1alpha1.GitHookTrigger
+
+	// EventTypes are the event types of GitHook. Git providers registering one event
+	// for several event types, ex. push for tag_push, send events not asked for.
+	EventTypes []v1alpha1.GitEvent
+
+	ConcurrencyPolicy v1alpha1.ConcurrencyPolicy
+	APIVersion        v1alpha1.PipelineAPIVersion
+	Workspaces        []v1alpha1.WorkspaceTemplate
 }
 
 // HandleRequest handles webhook request
 func (ra *ReceiveAdapter) HandleRequest(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
 	payload, err := ra.HookServer.Parse(r)
 	if err != nil {
 		log.Println(err)
 		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	message, err := ra.HandleEvent(payload, body, r.Header)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
 	}
 
-	ra.HandleEvent(payload, r.Header)
+	fmt.Fprintln(w, message)
 }
 
-// HandleEvent is invoked whenever an event comes in from git
-func (ra *ReceiveAdapter) HandleEvent(payload interface{}, header http.Header) {
-	err := ra.handleEvent(payload, header)
+// HandleEvent is invoked whenever an event comes in from git.
+// It returns a message describing what has been done with the event.
+func (ra *ReceiveAdapter) HandleEvent(payload interface{}, body []byte, header http.Header) (string, error) {
+	message, err := ra.handleEvent(payload, body, header)
 	if err != nil {
 		log.Printf("unexpected error handling git event: %s", err)
+		return "", err
 	}
+
+	log.Println(message)
+	return message, nil
 }
 
-func (ra *ReceiveAdapter) handleEvent(payload interface{}, header http.Header) error {
-	gitEventType := header.Get("X-" + ra.HookServer.GetEventHeader())
+func (ra *ReceiveAdapter) handleEvent(payload interface{}, body []byte, header http.Header) (string, error) {
+	options := ra.HookServer.BuildOptionFromPayload(payload)
+
+	// providers without event header give event type and delivery id in the payload
+	if eventHeader := ra.HookServer.GetEventHeader(); eventHeader != "" {
+		options.EventType = header.Get("X-" + eventHeader)
+	}
+
+	if deliveryHeader := ra.HookServer.GetDeliveryHeader(); deliveryHeader != "" {
+		options.DeliveryID = header.Get("X-" + deliveryHeader)
+	}
+
+	gitEventType := options.EventType
 
 	log.Printf("Handling %s", gitEventType)
 
 	if gitEventType == "" {
-		return fmt.Errorf("invalid event: %s", gitEventType)
+		return "", fmt.Errorf("invalid event: %s", gitEventType)
 	}
 
-	options := ra.HookServer.BuildOptionFromPayload(payload)
 	options.Namespace = ra.Namespace
 	options.Prefix = ra.Name
-	options.RunSpecJSON = ra.RunSpecJSON
 
-	pipelineRun, err := ra.TektonClient.CreatePipelineRun(options)
+	if len(ra.Triggers) == 0 {
+		if reason := matchEventTypes(gitEventNames(ra.EventTypes), options); reason != "" {
+			return fmt.Sprintf("event %s skipped: %s", gitEventType, reason), nil
+		}
+
+		runSpecJSON, err := ra.runSpecJSONFor(options.RepoName)
+
+		if err != nil {
+			return "", err
+		}
+		options.RunSpecJSON = runSpecJSON
+	}
+
+	reason, err := filterEvent(ra.Filters, options)
+
+	if err != nil {
+		return "", err
+	}
+
+	if reason != "" {
+		return fmt.Sprintf("event %s skipped: %s", gitEventType, reason), nil
+	}
+
+	if (hasPathFilters(ra.Filters) || hasTriggerPathFilters(ra.Triggers)) && options.ChangedFiles == nil && options.PullRequestNumber > 0 {
+		options.ChangedFiles, err = ra.listChangedFiles(options.RepoOwner, options.RepoName, options.PullRequestNumber)
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	reason, err = filterPaths(ra.Filters, options.ChangedFiles)
+
+	if err != nil {
+		return "", err
+	}
+
+	if reason != "" {
+		return fmt.Sprintf("event %s skipped: %s", gitEventType, reason), nil
+	}
+
+	options.RenderMode = string(ra.RenderMode)
+	options.Params = ra.Params
+	options.ConcurrencyPolicy = ra.ConcurrencyPolicy
+	options.APIVersion = ra.APIVersion
+	options.Workspaces = ra.Workspaces
+
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &options.Payload); err != nil {
+			return "", fmt.Errorf("failed to decode payload: %s", err)
+		}
+	}
+
+	if len(ra.Triggers) > 0 {
+		return ra.runTriggers(options)
+	}
+
+	return ra.createPipelineRun(options)
+}
+
+// runTriggers creates a pipelinerun for every trigger matching the event.
+// Failing triggers do not prevent the others from creating their pipelinerun.
+func (ra *ReceiveAdapter) runTriggers(options tekton.PipelineOptions) (string, error) {
+	messages := []string{}
+	failures := []string{}
+	created := 0
+
+	for _, trigger := range ra.Triggers {
+		// triggers default to the event types of GitHook
+		if len(trigger.EventTypes) == 0 {
+			trigger.EventTypes = ra.EventTypes
+		}
+
+		reason, err := matchTrigger(trigger, options)
+
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+
+		if reason != "" {
+			messages = append(messages, fmt.Sprintf("trigger %s skipped: %s", trigger.Name, reason))
+			continue
+		}
+
+		triggerOptions := options
+		triggerOptions.Trigger = trigger.Name
+		triggerOptions.RunSpecJSON, err = triggerRunSpecJSON(trigger)
+
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+
+		message, err := ra.createPipelineRun(triggerOptions)
+
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("trigger %s: %s", trigger.Name, err))
+			continue
+		}
+
+		created++
+		messages = append(messages, fmt.Sprintf("trigger %s: %s", trigger.Name, message))
+	}
+
+	return triggersResult(options.EventType, messages, failures, created)
+}
+
+// triggersResult returns the message of event handled by triggers. The event fails only when
+// no trigger created a pipelinerun.
+func triggersResult(eventType string, messages, failures []string, created int) (string, error) {
+	if len(failures) == 0 {
+		return fmt.Sprintf("event %s handled: %s", eventType, strings.Join(messages, "; ")), nil
+	}
+
+	log.Printf("event %s triggers failed: %s", eventType, strings.Join(failures, "; "))
+
+	if created == 0 {
+		log.Println(strings.Join(messages, "; "))
+		return "", fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+
+	return fmt.Sprintf("event %s partially handled: %s; failed: %s", eventType, strings.Join(messages, "; "), strings.Join(failures, "; ")), nil
+}
+
+// createPipelineRun creates pipelinerun of options and returns a message describing it
+func (ra *ReceiveAdapter) createPipelineRun(options tekton.PipelineOptions) (string, error) {
+	pipelineRun, queued, err := ra.TektonClient.CreatePipelineRun(options)
 
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	log.Printf("create pipeline run successfully %s", pipelineRun.Name)
+	if queued {
+		return fmt.Sprintf("pipeline run queued until running pipeline runs of %s finish", concurrencyKey(options)), nil
+	}
+
+	return fmt.Sprintf("create pipeline run successfully %s", pipelineRun.GetName()), nil
+}
+
+// listChangedFiles returns files changed by pull request or nil if the git provider cannot tell
+func (ra *ReceiveAdapter) listChangedFiles(repoOwner, repoName string, number int) ([]string, error) {
+	if ra.GitClient == nil {
+		return nil, nil
+	}
+
+	files, err := ra.GitClient.ListChangedFiles(ra.repoHookOptions(repoOwner, repoName), number)
+
+	if err == model.ErrNotSupported {
+		log.Printf("path filters are not applied to pull request %d: changed files are %s", number, err)
+		return nil, nil
+	}
+
+	return files, err
+}
+
+// concurrencyKey describes the branch or pull request pipeline runs of which run one at a time
+func concurrencyKey(options tekton.PipelineOptions) string {
+	if options.PullRequestNumber > 0 {
+		return fmt.Sprintf("pull request %d", options.PullRequestNumber)
+	}
 
-	return nil
+	return fmt.Sprintf("branch %s", options.GitBranch)
 }
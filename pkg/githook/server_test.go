@@ -0,0 +1,68 @@
+package githook
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+// fakeHookServer returns the options of every payload
+type fakeHookServer struct {
+	options tekton.PipelineOptions
+}
+
+func (s *fakeHookServer) GetEventHeader() string {
+	return ""
+}
+
+func (s *fakeHookServer) GetDeliveryHeader() string {
+	return ""
+}
+
+func (s *fakeHookServer) Parse(r *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func (s *fakeHookServer) BuildOptionFromPayload(payload interface{}) tekton.PipelineOptions {
+	return s.options
+}
+
+func TestHandleEventSkipsEventTypesOfGitHook(t *testing.T) {
+	branchPush := tekton.PipelineOptions{EventType: "push", GitBranch: "main"}
+	tags := []v1alpha1.GitEvent{v1alpha1.EventTagPush}
+
+	testcases := []struct {
+		triggers []v1alpha1.GitHookTrigger
+		expected string
+	}{
+		{
+			expected: "event push skipped: event push is not one of [tag_push]",
+		},
+		{
+			triggers: []v1alpha1.GitHookTrigger{{Name: "release"}},
+			expected: "trigger release skipped: event push is not one of [tag_push]",
+		},
+	}
+
+	for _, testcase := range testcases {
+		ra := &ReceiveAdapter{
+			HookServer:  &fakeHookServer{options: branchPush},
+			RunSpecJSON: `{"pipelineRef":{"name":"release"}}`,
+			Triggers:    testcase.triggers,
+			EventTypes:  tags,
+		}
+
+		message, err := ra.handleEvent(nil, nil, http.Header{})
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !strings.Contains(message, testcase.expected) {
+			t.Fatalf("expected %q but got %q", testcase.expected, message)
+		}
+	}
+}
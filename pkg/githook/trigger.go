@@ -0,0 +1,184 @@
+package githook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+// gitEventTypes maps event types sent by git providers to event types of GitHook.
+// Github, gogs and gitea send the other event types of GitHook as is.
+var gitEventTypes = map[string]string{
+	// gitlab
+	"Push Hook":          "push",
+	"Tag Push Hook":      "tag_push",
+	"Issue Hook":         "issues",
+	"Note Hook":          "issue_comment",
+	"Merge Request Hook": "pull_request",
+	"Pipeline Hook":      "pipeline",
+	"Wiki Page Hook":     "wiki",
+
+	// github
+	"pull_request_review":         "review",
+	"pull_request_review_comment": "review_comment",
+	"gollum":                      "wiki",
+
+	// gitea
+	"pull_request_approved": "review",
+	"pull_request_rejected": "review",
+	"pull_request_comment":  "issue_comment",
+
+	// bitbucket
+	"repo:push":           "push",
+	"pullrequest:created": "pull_request",
+	"pullrequest:updated": "pull_request",
+
+	// bitbucket server
+	"repo:refs_changed":   "push",
+	"pr:opened":           "pull_request",
+	"pr:from_ref_updated": "pull_request",
+
+	// azure devops
+	"git.push":                "push",
+	"git.pullrequest.created": "pull_request",
+	"git.pullrequest.updated": "pull_request",
+}
+
+// gitEventAliases are the other event types of GitHook matching an event type
+var gitEventAliases = map[string][]string{
+	"tag_push": {"push"},
+}
+
+// gitEventType returns the event type of GitHook of event type sent by git provider
+func gitEventType(eventType string) string {
+	if gitEvent, ok := gitEventTypes[eventType]; ok {
+		return gitEvent
+	}
+
+	return eventType
+}
+
+// gitEventTypesOf returns the event types of GitHook matching the event. Pushes of tags
+// match tag_push as well since most git providers send them as push events.
+func gitEventTypesOf(options tekton.PipelineOptions) []string {
+	gitEvent := gitEventType(options.EventType)
+	eventTypes := append([]string{gitEvent}, gitEventAliases[gitEvent]...)
+
+	if gitEvent == "push" && options.GitTag != "" {
+		eventTypes = append(eventTypes, "tag_push")
+	}
+
+	return eventTypes
+}
+
+// matchEventTypes returns the reason why the event does not match event types or empty if it does
+func matchEventTypes(eventTypes []string, options tekton.PipelineOptions) string {
+	if len(eventTypes) == 0 {
+		return ""
+	}
+
+	gitEvents := gitEventTypesOf(options)
+	for _, expected := range eventTypes {
+		for _, gitEvent := range gitEvents {
+			if expected == gitEvent {
+				return ""
+			}
+		}
+	}
+
+	gitEvent := gitEvents[0]
+
+	return fmt.Sprintf("event %s is not one of %v", gitEvent, eventTypes)
+}
+
+// gitEventNames returns the names of event types of GitHook
+func gitEventNames(gitEvents []v1alpha1.GitEvent) []string {
+	eventTypes := make([]string, 0, len(gitEvents))
+	for _, gitEvent := range gitEvents {
+		eventTypes = append(eventTypes, string(gitEvent))
+	}
+
+	return eventTypes
+}
+
+// matchTrigger returns the reason why the event does not match trigger or empty if it does
+func matchTrigger(trigger v1alpha1.GitHookTrigger, options tekton.PipelineOptions) (string, error) {
+	if reason := matchEventTypes(gitEventNames(trigger.EventTypes), options); reason != "" {
+		return reason, nil
+	}
+
+	reason, err := filterEvent(trigger.Filters, options)
+
+	if err != nil || reason != "" {
+		return reason, err
+	}
+
+	reason, err = filterPaths(trigger.Filters, options.ChangedFiles)
+
+	if err != nil || reason != "" {
+		return reason, err
+	}
+
+	if trigger.Expression == "" {
+		return "", nil
+	}
+
+	matched, err := tekton.EvaluateExpression(trigger.Expression, options)
+
+	if err != nil {
+		return "", fmt.Errorf("trigger %s: %s", trigger.Name, err)
+	}
+
+	if !matched {
+		return fmt.Sprintf("expression %q is not true", trigger.Expression), nil
+	}
+
+	return "", nil
+}
+
+// triggerRunSpecJSON returns pipelinerun spec in json format of trigger
+// with its pipeline reference
+func triggerRunSpecJSON(trigger v1alpha1.GitHookTrigger) (string, error) {
+	var runSpecJSON []byte
+
+	if trigger.PipelineRunSpec != nil && len(trigger.PipelineRunSpec.Raw) > 0 {
+		runSpecJSON = trigger.PipelineRunSpec.Raw
+	} else {
+		var err error
+		if runSpecJSON, err = json.Marshal(trigger.RunSpec); err != nil {
+			return "", err
+		}
+	}
+
+	if trigger.PipelineRef == nil {
+		return string(runSpecJSON), nil
+	}
+
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(runSpecJSON, &spec); err != nil {
+		return "", fmt.Errorf("trigger %s: failed to parse pipelinerun spec: %s", trigger.Name, err)
+	}
+
+	delete(spec, "pipelineSpec")
+	spec["pipelineRef"] = trigger.PipelineRef
+
+	output, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+// hasTriggerPathFilters checks if path filters are configured by any trigger
+func hasTriggerPathFilters(triggers []v1alpha1.GitHookTrigger) bool {
+	for _, trigger := range triggers {
+		if hasPathFilters(trigger.Filters) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,200 @@
+package githook
+
+import (
+	"strings"
+	"testing"
+
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"gitlab.com/pongsatt/githook/api/v1alpha1"
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGitEventType(t *testing.T) {
+	testcases := map[string]string{
+		"push":                    "push",
+		"Merge Request Hook":      "pull_request",
+		"repo:refs_changed":       "push",
+		"git.pullrequest.created": "pull_request",
+		"pull_request_approved":   "review",
+		"pull_request_review":     "review",
+		"Tag Push Hook":           "tag_push",
+		"Pipeline Hook":           "pipeline",
+		"gollum":                  "wiki",
+	}
+
+	for eventType, expected := range testcases {
+		if gitEvent := gitEventType(eventType); gitEvent != expected {
+			t.Fatalf("expected event type %s of %s but got %s", expected, eventType, gitEvent)
+		}
+	}
+}
+
+func TestMatchTrigger(t *testing.T) {
+	test := v1alpha1.GitHookTrigger{Name: "test", EventTypes: []v1alpha1.GitEvent{"pull_request"}}
+	deploy := v1alpha1.GitHookTrigger{
+		Name:       "deploy",
+		EventTypes: []v1alpha1.GitEvent{"push"},
+		Filters:    &v1alpha1.GitHookFilters{Branches: []string{"main"}},
+	}
+	tag := v1alpha1.GitHookTrigger{Name: "tag", EventTypes: []v1alpha1.GitEvent{"tag_push"}}
+	review := v1alpha1.GitHookTrigger{Name: "review", EventTypes: []v1alpha1.GitEvent{"review"}}
+	release := v1alpha1.GitHookTrigger{
+		Name:       "release",
+		Expression: `{{ eq .Payload.ref_type "tag" }}`,
+	}
+
+	testcases := []struct {
+		trigger  v1alpha1.GitHookTrigger
+		options  tekton.PipelineOptions
+		expected string
+	}{
+		{
+			trigger:  test,
+			options:  tekton.PipelineOptions{EventType: "Merge Request Hook", GitBranch: "feature"},
+			expected: "",
+		},
+		{
+			trigger:  test,
+			options:  tekton.PipelineOptions{EventType: "push", GitBranch: "main"},
+			expected: "event push is not one of [pull_request]",
+		},
+		{
+			trigger:  deploy,
+			options:  tekton.PipelineOptions{EventType: "push", GitBranch: "main"},
+			expected: "",
+		},
+		{
+			trigger:  deploy,
+			options:  tekton.PipelineOptions{EventType: "push", GitBranch: "develop"},
+			expected: `branch "develop" does not match any of branch filters`,
+		},
+		{
+			trigger:  tag,
+			options:  tekton.PipelineOptions{EventType: "push", GitTag: "v1.0.0"},
+			expected: "",
+		},
+		{
+			trigger:  tag,
+			options:  tekton.PipelineOptions{EventType: "Tag Push Hook", GitTag: "v1.0.0"},
+			expected: "",
+		},
+		{
+			trigger:  tag,
+			options:  tekton.PipelineOptions{EventType: "push", GitBranch: "main"},
+			expected: "event push is not one of [tag_push]",
+		},
+		{
+			trigger:  deploy,
+			options:  tekton.PipelineOptions{EventType: "Tag Push Hook", GitTag: "v1.0.0"},
+			expected: `tag "v1.0.0" is skipped because only branch filters are configured`,
+		},
+		{
+			trigger:  review,
+			options:  tekton.PipelineOptions{EventType: "pull_request_review", GitBranch: "feature"},
+			expected: "",
+		},
+		{
+			trigger:  release,
+			options:  tekton.PipelineOptions{EventType: "create", GitTag: "v1.0.0", Payload: map[string]interface{}{"ref_type": "tag"}},
+			expected: "",
+		},
+		{
+			trigger:  release,
+			options:  tekton.PipelineOptions{EventType: "create", GitBranch: "main", Payload: map[string]interface{}{"ref_type": "branch"}},
+			expected: `expression "{{ eq .Payload.ref_type \"tag\" }}" is not true`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		reason, err := matchTrigger(testcase.trigger, testcase.options)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if reason != testcase.expected {
+			t.Fatalf("expected trigger %s to be skipped with %q but got %q", testcase.trigger.Name, testcase.expected, reason)
+		}
+	}
+
+	_, err := matchTrigger(v1alpha1.GitHookTrigger{Name: "broken", Expression: "{{ .Vars.BRANCH.missing }}"}, tekton.PipelineOptions{Payload: map[string]interface{}{}})
+
+	if err == nil || !strings.Contains(err.Error(), "trigger broken") {
+		t.Fatalf("expected expression error of trigger but got %v", err)
+	}
+}
+
+func TestTriggerRunSpecJSON(t *testing.T) {
+	testcases := []struct {
+		trigger  v1alpha1.GitHookTrigger
+		expected string
+	}{
+		{
+			trigger: v1alpha1.GitHookTrigger{
+				RunSpec: tektonv1alpha1.PipelineRunSpec{PipelineRef: tektonv1alpha1.PipelineRef{Name: "test"}},
+			},
+			expected: `"pipelineRef":{"name":"test"}`,
+		},
+		{
+			trigger: v1alpha1.GitHookTrigger{
+				PipelineRef:     &tektonv1alpha1.PipelineRef{Name: "deploy"},
+				PipelineRunSpec: &runtime.RawExtension{Raw: []byte(`{"pipelineSpec":{"tasks":[]},"timeout":"1h"}`)},
+			},
+			expected: `{"pipelineRef":{"name":"deploy"},"timeout":"1h"}`,
+		},
+		{
+			trigger: v1alpha1.GitHookTrigger{
+				PipelineRef: &tektonv1alpha1.PipelineRef{Name: "release"},
+				RunSpec:     tektonv1alpha1.PipelineRunSpec{ServiceAccount: "releaser"},
+			},
+			expected: `"pipelineRef":{"name":"release"}`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		runSpecJSON, err := triggerRunSpecJSON(testcase.trigger)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if !strings.Contains(runSpecJSON, testcase.expected) {
+			t.Fatalf("expected %s in pipelinerun spec but got %s", testcase.expected, runSpecJSON)
+		}
+	}
+}
+
+func TestTriggersResult(t *testing.T) {
+	testcases := []struct {
+		messages []string
+		failures []string
+		created  int
+		expected string
+		err      string
+	}{
+		{messages: []string{"trigger ci: created"}, created: 1, expected: "event push handled: trigger ci: created"},
+		{messages: []string{"trigger docs skipped: no match"}, failures: []string{"trigger ci: quota exceeded"}, err: "trigger ci: quota exceeded"},
+		{
+			messages: []string{"trigger ci: created"},
+			failures: []string{"trigger deploy: quota exceeded"},
+			created:  1,
+			expected: "event push partially handled: trigger ci: created; failed: trigger deploy: quota exceeded",
+		},
+	}
+
+	for _, testcase := range testcases {
+		message, err := triggersResult("push", testcase.messages, testcase.failures, testcase.created)
+
+		if testcase.err != "" {
+			if err == nil || err.Error() != testcase.err {
+				t.Fatalf("expected error %q but got %v", testcase.err, err)
+			}
+			continue
+		}
+
+		if err != nil || message != testcase.expected {
+			t.Fatalf("expected %q but got %q, %v", testcase.expected, message, err)
+		}
+	}
+}
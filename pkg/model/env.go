@@ -0,0 +1,16 @@
+package model
+
+// Environment variables passed by the controller to the receive adapter
+const (
+	// EnvSecret environment variable containing git secret token
+	EnvSecret = "SECRET_TOKEN"
+
+	// EnvAccessToken environment variable containing git access token
+	EnvAccessToken = "ACCESS_TOKEN"
+
+	// EnvCABundle environment variable containing CA certificates trusted when calling git provider
+	EnvCABundle = "CA_BUNDLE"
+
+	// EnvGithubAppPrivateKey environment variable containing private key of github app
+	EnvGithubAppPrivateKey = "GITHUB_APP_PRIVATE_KEY"
+)
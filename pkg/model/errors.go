@@ -0,0 +1,6 @@
+package model
+
+import "errors"
+
+// ErrNotSupported is returned when a functionality is not provided by the git provider
+var ErrNotSupported = errors.New("not supported by git provider")
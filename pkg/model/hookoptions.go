@@ -1,5 +1,12 @@
 package model
 
+// GithubAppOptions keeps github app credentials
+type GithubAppOptions struct {
+	AppID          int64
+	InstallationID int64
+	PrivateKey     string
+}
+
 // HookOptions keeps webhook options
 type HookOptions struct {
 	AccessToken string
@@ -9,5 +16,16 @@ type HookOptions struct {
 	BaseURL     string
 	URL         string
 	Owner       string
-	Events      []string
+
+	// Events are the events of the git provider webhook is registered for, see ProviderEvents
+	Events []string
+
+	// CABundle is PEM encoded CA certificates trusted when calling the git provider
+	CABundle string
+
+	// Organization registers webhook on owner (organization or group) instead of project
+	Organization bool
+
+	// GithubApp authenticates as github app installation instead of access token
+	GithubApp *GithubAppOptions
 }
@@ -0,0 +1,34 @@
+package model
+
+// CommitState is the state of a pipeline run reported on a commit
+type CommitState string
+
+const (
+	// CommitStatePending pipeline run is created but not started
+	CommitStatePending CommitState = "pending"
+
+	// CommitStateRunning pipeline run is running
+	CommitStateRunning CommitState = "running"
+
+	// CommitStateSuccess pipeline run succeeded
+	CommitStateSuccess CommitState = "success"
+
+	// CommitStateFailure pipeline run failed
+	CommitStateFailure CommitState = "failure"
+
+	// CommitStateCancelled pipeline run was cancelled
+	CommitStateCancelled CommitState = "cancelled"
+)
+
+// CommitStatus keeps the status of a pipeline run reported on a commit
+type CommitStatus struct {
+	SHA         string
+	Branch      string
+	State       CommitState
+	Context     string
+	Description string
+	TargetURL   string
+
+	// CheckRunID is the id of the github check run to be updated or empty to create new one
+	CheckRunID string
+}
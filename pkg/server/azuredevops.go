@@ -0,0 +1,234 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+const (
+	// azureDevOpsHeaderSecret carries the secret token of manually configured service hooks
+	azureDevOpsHeaderSecret = "X-Githook-Secret"
+
+	azureDevOpsPushEvent               = "git.push"
+	azureDevOpsPullRequestCreatedEvent = "git.pullrequest.created"
+	azureDevOpsPullRequestUpdatedEvent = "git.pullrequest.updated"
+
+	azureDevOpsZeroObjectID = "0000000000000000000000000000000000000000"
+)
+
+// ErrAzureDevOpsUnauthorized is returned when service hook request carries no valid secret token
+var ErrAzureDevOpsUnauthorized = errors.New("basic auth password or " + azureDevOpsHeaderSecret + " header does not match secret token")
+
+// AzureDevOpsEvent is the envelope of azure devops service hook payloads
+type AzureDevOpsEvent struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscriptionId"`
+	EventType      string `json:"eventType"`
+	PublisherID    string `json:"publisherId"`
+}
+
+// AzureDevOpsIdentity is an azure devops user
+type AzureDevOpsIdentity struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+// AzureDevOpsGitUser is author or committer of a commit
+type AzureDevOpsGitUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// AzureDevOpsRepository is an azure repos repository
+type AzureDevOpsRepository struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Project struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"project"`
+	DefaultBranch string `json:"defaultBranch"`
+	RemoteURL     string `json:"remoteUrl"`
+}
+
+// AzureDevOpsPush is the resource of git.push events
+type AzureDevOpsPush struct {
+	PushID  int `json:"pushId"`
+	Commits []struct {
+		CommitID  string             `json:"commitId"`
+		Author    AzureDevOpsGitUser `json:"author"`
+		Committer AzureDevOpsGitUser `json:"committer"`
+		Comment   string             `json:"comment"`
+	} `json:"commits"`
+	RefUpdates []struct {
+		Name        string `json:"name"`
+		OldObjectID string `json:"oldObjectId"`
+		NewObjectID string `json:"newObjectId"`
+	} `json:"refUpdates"`
+	Repository AzureDevOpsRepository `json:"repository"`
+	PushedBy   AzureDevOpsIdentity   `json:"pushedBy"`
+}
+
+// AzureDevOpsPullRequest is the resource of git.pullrequest events
+type AzureDevOpsPullRequest struct {
+	PullRequestID         int                   `json:"pullRequestId"`
+	Status                string                `json:"status"`
+	Title                 string                `json:"title"`
+	SourceRefName         string                `json:"sourceRefName"`
+	TargetRefName         string                `json:"targetRefName"`
+	Repository            AzureDevOpsRepository `json:"repository"`
+	CreatedBy             AzureDevOpsIdentity   `json:"createdBy"`
+	LastMergeSourceCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+}
+
+// AzureDevOpsPushPayload is the git.push service hook payload
+type AzureDevOpsPushPayload struct {
+	AzureDevOpsEvent
+	Resource AzureDevOpsPush `json:"resource"`
+}
+
+// AzureDevOpsPullRequestPayload is the git.pullrequest.created and git.pullrequest.updated service hook payload
+type AzureDevOpsPullRequestPayload struct {
+	AzureDevOpsEvent
+	Resource AzureDevOpsPullRequest `json:"resource"`
+}
+
+// AzureDevOpsServer provides azure devops git functionalities
+type AzureDevOpsServer struct {
+	secretToken string
+}
+
+// NewAzureDevOpsServer creates new azure devops provider
+func NewAzureDevOpsServer(secretToken string) (*AzureDevOpsServer, error) {
+	return &AzureDevOpsServer{secretToken}, nil
+}
+
+// GetEventHeader returns empty since event type is given in the payload
+func (git *AzureDevOpsServer) GetEventHeader() string {
+	return ""
+}
+
+// GetDeliveryHeader returns empty since delivery id is given in the payload
+func (git *AzureDevOpsServer) GetDeliveryHeader() string {
+	return ""
+}
+
+func (git *AzureDevOpsServer) authorize(r *http.Request) error {
+	secret := r.Header.Get(azureDevOpsHeaderSecret)
+	if _, password, ok := r.BasicAuth(); ok {
+		secret = password
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(git.secretToken)) != 1 {
+		return ErrAzureDevOpsUnauthorized
+	}
+
+	return nil
+}
+
+// Parse returns azure devops payload
+func (git *AzureDevOpsServer) Parse(r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost {
+		return nil, fmt.Errorf("invalid HTTP Method")
+	}
+
+	if err := git.authorize(r); err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return nil, fmt.Errorf("error parsing payload")
+	}
+
+	event := AzureDevOpsEvent{}
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+
+	switch event.EventType {
+	case azureDevOpsPushEvent:
+		payload := AzureDevOpsPushPayload{}
+		err = json.Unmarshal(body, &payload)
+		return payload, err
+	case azureDevOpsPullRequestCreatedEvent, azureDevOpsPullRequestUpdatedEvent:
+		payload := AzureDevOpsPullRequestPayload{}
+		err = json.Unmarshal(body, &payload)
+		return payload, err
+	}
+
+	return nil, fmt.Errorf("event %s not defined to be parsed", event.EventType)
+}
+
+func azureDevOpsUserName(user AzureDevOpsIdentity) string {
+	return firstNonEmpty(user.UniqueName, user.DisplayName)
+}
+
+// BuildOptionFromPayload builds pipeline option from payload information
+func (git *AzureDevOpsServer) BuildOptionFromPayload(payload interface{}) tekton.PipelineOptions {
+	switch payload.(type) {
+	case AzureDevOpsPushPayload:
+		p := payload.(AzureDevOpsPushPayload)
+		options := tekton.PipelineOptions{
+			GitURL:     p.Resource.Repository.RemoteURL,
+			RepoOwner:  p.Resource.Repository.Project.Name,
+			RepoName:   p.Resource.Repository.Name,
+			EventType:  p.EventType,
+			DeliveryID: p.ID,
+			Author:     azureDevOpsUserName(p.Resource.PushedBy),
+			Committer:  azureDevOpsUserName(p.Resource.PushedBy),
+			Sender:     azureDevOpsUserName(p.Resource.PushedBy),
+		}
+
+		// a push may update several references, the first one which is not deleted triggers the run
+		for _, refUpdate := range p.Resource.RefUpdates {
+			if refUpdate.NewObjectID == azureDevOpsZeroObjectID {
+				continue
+			}
+
+			options.GitRevision = refUpdate.Name
+			options.GitCommit = refUpdate.NewObjectID
+			options.GitBranch, options.GitTag = refToBranchOrTag(refUpdate.Name)
+			break
+		}
+
+		for _, commit := range p.Resource.Commits {
+			if commit.CommitID == options.GitCommit {
+				options.Author = firstNonEmpty(commit.Author.Name, options.Author)
+				options.Committer = firstNonEmpty(commit.Committer.Name, options.Committer)
+			}
+		}
+
+		return options
+	case AzureDevOpsPullRequestPayload:
+		p := payload.(AzureDevOpsPullRequestPayload)
+		sourceBranch := strings.TrimPrefix(p.Resource.SourceRefName, branchRefPrefix)
+		return tekton.PipelineOptions{
+			GitURL:            p.Resource.Repository.RemoteURL,
+			GitRevision:       sourceBranch,
+			GitCommit:         p.Resource.LastMergeSourceCommit.CommitID,
+			GitBranch:         sourceBranch,
+			RepoOwner:         p.Resource.Repository.Project.Name,
+			RepoName:          p.Resource.Repository.Name,
+			EventType:         p.EventType,
+			DeliveryID:        p.ID,
+			Author:            azureDevOpsUserName(p.Resource.CreatedBy),
+			Committer:         azureDevOpsUserName(p.Resource.CreatedBy),
+			Sender:            azureDevOpsUserName(p.Resource.CreatedBy),
+			PullRequestNumber: p.Resource.PullRequestID,
+			SourceBranch:      sourceBranch,
+			TargetBranch:      strings.TrimPrefix(p.Resource.TargetRefName, branchRefPrefix),
+		}
+	}
+	return tekton.PipelineOptions{}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const azureDevOpsPushBody = `{
+	"id": "03c164c2-8912-4d5e-8009-3707d5f83734",
+	"eventType": "git.push",
+	"publisherId": "tfs",
+	"resource": {
+		"commits": [{"commitId": "33b55f7cb7e7e245323987634f960cf4a6e6bc74", "author": {"name": "Jamal Hartnett"}, "committer": {"name": "Jamal Hartnett"}}],
+		"refUpdates": [{"name": "refs/heads/master", "oldObjectId": "aad331d8d3b131fa9ae03cf5e53965b51942618a", "newObjectId": "33b55f7cb7e7e245323987634f960cf4a6e6bc74"}],
+		"repository": {"name": "Fabrikam-Fiber-Git", "project": {"name": "Fabrikam-Fiber-Git"}, "remoteUrl": "https://dev.azure.com/fabrikam/Fabrikam-Fiber-Git/_git/Fabrikam-Fiber-Git"},
+		"pushedBy": {"displayName": "Jamal Hartnett", "uniqueName": "fabrikamfiber4@hotmail.com"}
+	}
+}`
+
+func TestAzureDevOpsParsePush(t *testing.T) {
+	git, _ := NewAzureDevOpsServer("secret")
+
+	r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(azureDevOpsPushBody))
+	r.SetBasicAuth("githook", "secret")
+
+	payload, err := git.Parse(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	options := git.BuildOptionFromPayload(payload)
+
+	if options.EventType != "git.push" || options.DeliveryID != "03c164c2-8912-4d5e-8009-3707d5f83734" {
+		t.Fatalf("expected event type and delivery id from payload but got %s and %s", options.EventType, options.DeliveryID)
+	}
+
+	if options.GitBranch != "master" || options.GitRevision != "refs/heads/master" || options.GitCommit != "33b55f7cb7e7e245323987634f960cf4a6e6bc74" {
+		t.Fatalf("unexpected git information %+v", options)
+	}
+
+	if options.RepoOwner != "Fabrikam-Fiber-Git" || options.RepoName != "Fabrikam-Fiber-Git" || options.Author != "Jamal Hartnett" {
+		t.Fatalf("unexpected event information %+v", options)
+	}
+}
+
+func TestAzureDevOpsParseAuthorization(t *testing.T) {
+	git, _ := NewAzureDevOpsServer("secret")
+
+	tests := []struct {
+		username string
+		password string
+		header   string
+		valid    bool
+	}{
+		{username: "githook", password: "secret", valid: true},
+		{header: "secret", valid: true},
+		{username: "githook", password: "other", header: "secret", valid: false},
+		{header: "other", valid: false},
+		{valid: false},
+	}
+
+	for i, test := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(azureDevOpsPushBody))
+		if test.password != "" {
+			r.SetBasicAuth(test.username, test.password)
+		}
+		if test.header != "" {
+			r.Header.Set(azureDevOpsHeaderSecret, test.header)
+		}
+
+		if _, err := git.Parse(r); (err == nil) != test.valid {
+			t.Fatalf("case %d: expected valid %v but got error %v", i, test.valid, err)
+		}
+	}
+}
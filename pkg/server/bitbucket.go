@@ -0,0 +1,119 @@
+package server
+
+import (
+	"net/http"
+
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+	"gopkg.in/go-playground/webhooks.v5/bitbucket"
+)
+
+const (
+	bitbucketHeaderEvent     = "Event-Key"
+	bitbucketHeaderDelivery  = "Request-UUID"
+	bitbucketHeaderSignature = "X-Hub-Signature"
+)
+
+// BitbucketServer provides bitbucket cloud git functionalities
+type BitbucketServer struct {
+	hook        *bitbucket.Webhook
+	secretToken string
+}
+
+// NewBitbucketServer creates new bitbucket cloud provider
+func NewBitbucketServer(secretToken string) (*BitbucketServer, error) {
+	hook, err := bitbucket.New()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &BitbucketServer{hook, secretToken}, nil
+}
+
+// GetEventHeader returns bitbucket event header
+func (git *BitbucketServer) GetEventHeader() string {
+	return bitbucketHeaderEvent
+}
+
+// GetDeliveryHeader returns bitbucket delivery header
+func (git *BitbucketServer) GetDeliveryHeader() string {
+	return bitbucketHeaderDelivery
+}
+
+// Parse returns bitbucket payload
+func (git *BitbucketServer) Parse(r *http.Request) (interface{}, error) {
+	if err := verifySignature(r, bitbucketHeaderSignature, git.secretToken); err != nil {
+		return nil, err
+	}
+
+	return git.hook.Parse(r,
+		bitbucket.RepoPushEvent,
+		bitbucket.PullRequestCreatedEvent,
+		bitbucket.PullRequestUpdatedEvent)
+}
+
+func bitbucketUserName(user bitbucket.Owner) string {
+	return firstNonEmpty(user.Username, user.DisplayName)
+}
+
+func bitbucketPullRequestOptions(pr bitbucket.PullRequest, repo bitbucket.Repository, actor bitbucket.Owner) tekton.PipelineOptions {
+	owner, name := splitFullName(repo.FullName)
+	return tekton.PipelineOptions{
+		GitURL:            repo.Links.HTML.Href,
+		GitRevision:       pr.Source.Branch.Name,
+		GitCommit:         pr.Source.Commit.Hash,
+		GitBranch:         pr.Source.Branch.Name,
+		RepoOwner:         owner,
+		RepoName:          name,
+		Author:            bitbucketUserName(pr.Author),
+		Committer:         bitbucketUserName(actor),
+		Sender:            bitbucketUserName(actor),
+		PullRequestNumber: int(pr.ID),
+		SourceBranch:      pr.Source.Branch.Name,
+		TargetBranch:      pr.Destination.Branch.Name,
+	}
+}
+
+// BuildOptionFromPayload builds pipeline option from payload information
+func (git *BitbucketServer) BuildOptionFromPayload(payload interface{}) tekton.PipelineOptions {
+	switch payload.(type) {
+	case bitbucket.RepoPushPayload:
+		p := payload.(bitbucket.RepoPushPayload)
+		owner, name := splitFullName(p.Repository.FullName)
+		options := tekton.PipelineOptions{
+			GitURL:    p.Repository.Links.HTML.Href,
+			RepoOwner: owner,
+			RepoName:  name,
+			Author:    bitbucketUserName(p.Actor),
+			Committer: bitbucketUserName(p.Actor),
+			Sender:    bitbucketUserName(p.Actor),
+		}
+
+		// a push may update several references, the first one which is not deleted triggers the run
+		for _, change := range p.Push.Changes {
+			if change.New.Name == "" {
+				continue
+			}
+
+			if change.New.Type == "tag" || change.New.Type == "annotated_tag" {
+				options.GitTag = change.New.Name
+				options.GitRevision = tagRefPrefix + change.New.Name
+			} else {
+				options.GitBranch = change.New.Name
+				options.GitRevision = branchRefPrefix + change.New.Name
+			}
+			options.GitCommit = change.New.Target.Hash
+			options.Author = firstNonEmpty(bitbucketUserName(change.New.Target.Author), options.Author)
+			break
+		}
+
+		return options
+	case bitbucket.PullRequestCreatedPayload:
+		p := payload.(bitbucket.PullRequestCreatedPayload)
+		return bitbucketPullRequestOptions(p.PullRequest, p.Repository, p.Actor)
+	case bitbucket.PullRequestUpdatedPayload:
+		p := payload.(bitbucket.PullRequestUpdatedPayload)
+		return bitbucketPullRequestOptions(p.PullRequest, p.Repository, p.Actor)
+	}
+	return tekton.PipelineOptions{}
+}
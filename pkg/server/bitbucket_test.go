@@ -0,0 +1,67 @@
+package server
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const bitbucketPushBody = `{
+	"actor": {"username": "alice", "display_name": "Alice"},
+	"repository": {"full_name": "team/repo", "links": {"html": {"href": "https://bitbucket.org/team/repo"}}},
+	"push": {"changes": [
+		{"new": null},
+		{"new": {"type": "tag", "name": "v1.0.0", "target": {"hash": "d3adb33f", "author": {"user": {"username": "bob"}}}}}
+	]}
+}`
+
+const bitbucketPullRequestBody = `{
+	"actor": {"username": "alice"},
+	"repository": {"full_name": "team/repo", "links": {"html": {"href": "https://bitbucket.org/team/repo"}}},
+	"pullrequest": {
+		"id": 7,
+		"author": {"username": "bob"},
+		"source": {"branch": {"name": "feature"}, "commit": {"hash": "c0ffee"}},
+		"destination": {"branch": {"name": "main"}}
+	}
+}`
+
+func TestBitbucketBuildOptionFromPayload(t *testing.T) {
+	git, _ := NewBitbucketServer("secret")
+
+	tests := []struct {
+		event    string
+		body     string
+		revision string
+		commit   string
+		branch   string
+		tag      string
+		number   int
+	}{
+		{event: "repo:push", body: bitbucketPushBody, revision: "refs/tags/v1.0.0", commit: "d3adb33f", tag: "v1.0.0"},
+		{event: "pullrequest:updated", body: bitbucketPullRequestBody, revision: "feature", commit: "c0ffee", branch: "feature", number: 7},
+	}
+
+	for _, test := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+		r.Header.Set("X-"+git.GetEventHeader(), test.event)
+		r.Header.Set("X-Hub-Signature", "sha256="+sign(sha256.New, "secret", test.body))
+
+		payload, err := git.Parse(r)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %s: %s", test.event, err)
+		}
+
+		options := git.BuildOptionFromPayload(payload)
+
+		if options.GitRevision != test.revision || options.GitCommit != test.commit || options.GitBranch != test.branch ||
+			options.GitTag != test.tag || options.PullRequestNumber != test.number {
+			t.Fatalf("unexpected options of %s: %+v", test.event, options)
+		}
+
+		if options.RepoOwner != "team" || options.RepoName != "repo" || options.GitURL != "https://bitbucket.org/team/repo" {
+			t.Fatalf("expected repository team/repo of %s but got %+v", test.event, options)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+	bitbucketserver "gopkg.in/go-playground/webhooks.v5/bitbucket-server"
+)
+
+const (
+	bitbucketServerHeaderEvent     = "Event-Key"
+	bitbucketServerHeaderDelivery  = "Request-Id"
+	bitbucketServerHeaderSignature = "X-Hub-Signature"
+
+	// bitbucketServerPullRequestFromRefUpdatedEvent is sent when source branch of pull request is updated
+	bitbucketServerPullRequestFromRefUpdatedEvent = "pr:from_ref_updated"
+)
+
+// BitbucketServerPullRequestFromRefUpdatedPayload is the bitbucket server pr:from_ref_updated payload
+type BitbucketServerPullRequestFromRefUpdatedPayload struct {
+	Date             bitbucketserver.Date        `json:"date"`
+	EventKey         bitbucketserver.Event       `json:"eventKey"`
+	Actor            bitbucketserver.User        `json:"actor"`
+	PullRequest      bitbucketserver.PullRequest `json:"pullRequest"`
+	PreviousFromHash string                      `json:"previousFromHash"`
+}
+
+// BitbucketServerServer provides bitbucket server and data center git functionalities
+type BitbucketServerServer struct {
+	secretToken string
+}
+
+// NewBitbucketServerServer creates new bitbucket server provider
+func NewBitbucketServerServer(secretToken string) (*BitbucketServerServer, error) {
+	return &BitbucketServerServer{secretToken}, nil
+}
+
+// GetEventHeader returns bitbucket server event header
+func (git *BitbucketServerServer) GetEventHeader() string {
+	return bitbucketServerHeaderEvent
+}
+
+// GetDeliveryHeader returns bitbucket server delivery header
+func (git *BitbucketServerServer) GetDeliveryHeader() string {
+	return bitbucketServerHeaderDelivery
+}
+
+// Parse returns bitbucket server payload
+func (git *BitbucketServerServer) Parse(r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost {
+		return nil, bitbucketserver.ErrInvalidHTTPMethod
+	}
+
+	event := r.Header.Get("X-" + bitbucketServerHeaderEvent)
+	if event == "" {
+		return nil, bitbucketserver.ErrMissingEventKeyHeader
+	}
+
+	if err := verifySignature(r, bitbucketServerHeaderSignature, git.secretToken); err != nil {
+		return nil, err
+	}
+
+	var payload interface{}
+
+	switch bitbucketserver.Event(event) {
+	case bitbucketserver.RepositoryReferenceChangedEvent:
+		payload = &bitbucketserver.RepositoryReferenceChangedPayload{}
+	case bitbucketserver.PullRequestOpenedEvent:
+		payload = &bitbucketserver.PullRequestOpenedPayload{}
+	case bitbucketServerPullRequestFromRefUpdatedEvent:
+		payload = &BitbucketServerPullRequestFromRefUpdatedPayload{}
+	default:
+		return nil, fmt.Errorf("event %s not defined to be parsed", event)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return nil, bitbucketserver.ErrParsingPayload
+	}
+
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func bitbucketServerUserName(user bitbucketserver.User) string {
+	return firstNonEmpty(user.Slug, user.Name, user.DisplayName)
+}
+
+// bitbucketServerCloneURL returns http clone url of repository
+func bitbucketServerCloneURL(repo bitbucketserver.Repository) string {
+	clones, _ := repo.Links["clone"].([]interface{})
+
+	for _, clone := range clones {
+		link, _ := clone.(map[string]interface{})
+		if name, _ := link["name"].(string); name == "http" || name == "https" {
+			href, _ := link["href"].(string)
+			return href
+		}
+	}
+
+	return ""
+}
+
+func bitbucketServerPullRequestOptions(pr bitbucketserver.PullRequest, actor bitbucketserver.User) tekton.PipelineOptions {
+	repo := pr.ToRef.Repository
+	return tekton.PipelineOptions{
+		GitURL:            bitbucketServerCloneURL(repo),
+		GitRevision:       pr.FromRef.ID,
+		GitCommit:         pr.FromRef.LatestCommit,
+		GitBranch:         pr.FromRef.DisplayId,
+		RepoOwner:         repo.Project.Key,
+		RepoName:          repo.Slug,
+		Author:            bitbucketServerUserName(pr.Author.User),
+		Committer:         bitbucketServerUserName(actor),
+		Sender:            bitbucketServerUserName(actor),
+		PullRequestNumber: int(pr.ID),
+		SourceBranch:      pr.FromRef.DisplayId,
+		TargetBranch:      pr.ToRef.DisplayId,
+	}
+}
+
+// BuildOptionFromPayload builds pipeline option from payload information
+func (git *BitbucketServerServer) BuildOptionFromPayload(payload interface{}) tekton.PipelineOptions {
+	switch payload.(type) {
+	case *bitbucketserver.RepositoryReferenceChangedPayload:
+		p := payload.(*bitbucketserver.RepositoryReferenceChangedPayload)
+		options := tekton.PipelineOptions{
+			GitURL:    bitbucketServerCloneURL(p.Repository),
+			RepoOwner: p.Repository.Project.Key,
+			RepoName:  p.Repository.Slug,
+			Author:    bitbucketServerUserName(p.Actor),
+			Committer: bitbucketServerUserName(p.Actor),
+			Sender:    bitbucketServerUserName(p.Actor),
+		}
+
+		// a push may update several references, the first one which is not deleted triggers the run
+		for _, change := range p.Changes {
+			if change.Type == "DELETE" {
+				continue
+			}
+
+			options.GitRevision = change.ReferenceId
+			options.GitCommit = change.ToHash
+			options.GitBranch, options.GitTag = refToBranchOrTag(change.ReferenceId)
+			break
+		}
+
+		return options
+	case *bitbucketserver.PullRequestOpenedPayload:
+		p := payload.(*bitbucketserver.PullRequestOpenedPayload)
+		return bitbucketServerPullRequestOptions(p.PullRequest, p.Actor)
+	case *BitbucketServerPullRequestFromRefUpdatedPayload:
+		p := payload.(*BitbucketServerPullRequestFromRefUpdatedPayload)
+		return bitbucketServerPullRequestOptions(p.PullRequest, p.Actor)
+	}
+	return tekton.PipelineOptions{}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const bitbucketServerPullRequestBody = `{
+	"eventKey": "pr:from_ref_updated",
+	"date": "2017-09-19T09:58:11+1000",
+	"actor": {"name": "admin", "slug": "admin"},
+	"pullRequest": {
+		"id": 2,
+		"fromRef": {"id": "refs/heads/feature/login", "displayId": "feature/login", "latestCommit": "8d2ad38c918fa6943859fca2176c89ea98b92a21", "repository": {"slug": "githook", "project": {"key": "APP"}}},
+		"toRef": {"id": "refs/heads/master", "displayId": "master", "repository": {
+			"slug": "githook",
+			"project": {"key": "APP"},
+			"links": {"clone": [{"href": "ssh://git@bitbucket.example.com:7999/app/githook.git", "name": "ssh"}, {"href": "https://bitbucket.example.com/scm/app/githook.git", "name": "http"}]}
+		}},
+		"author": {"user": {"name": "alice", "slug": "alice"}}
+	},
+	"previousFromHash": "99f3ea32043ba3ecaa28de6046b420de70257d80"
+}`
+
+func TestBitbucketServerParsePullRequest(t *testing.T) {
+	git, _ := NewBitbucketServerServer("secret")
+
+	r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(bitbucketServerPullRequestBody))
+	r.Header.Set("X-Event-Key", "pr:from_ref_updated")
+	r.Header.Set("X-Hub-Signature", "sha256="+sign(sha256.New, "secret", bitbucketServerPullRequestBody))
+
+	payload, err := git.Parse(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	options := git.BuildOptionFromPayload(payload)
+
+	if options.GitURL != "https://bitbucket.example.com/scm/app/githook.git" {
+		t.Fatalf("expected http clone url but got %s", options.GitURL)
+	}
+
+	if options.GitBranch != "feature/login" || options.GitCommit != "8d2ad38c918fa6943859fca2176c89ea98b92a21" || options.TargetBranch != "master" {
+		t.Fatalf("unexpected git information %+v", options)
+	}
+
+	if options.RepoOwner != "APP" || options.RepoName != "githook" || options.Author != "alice" || options.Committer != "admin" || options.Sender != "admin" || options.PullRequestNumber != 2 {
+		t.Fatalf("unexpected event information %+v", options)
+	}
+}
+
+func TestBitbucketServerParseInvalidSignature(t *testing.T) {
+	git, _ := NewBitbucketServerServer("secret")
+
+	for _, signature := range []string{"", "sha", "sha256=" + sign(sha256.New, "other", bitbucketServerPullRequestBody)} {
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(bitbucketServerPullRequestBody))
+		r.Header.Set("X-Event-Key", "pr:from_ref_updated")
+		r.Header.Set("X-Hub-Signature", signature)
+
+		if _, err := git.Parse(r); err == nil {
+			t.Fatalf("expected error for signature %q", signature)
+		}
+	}
+}
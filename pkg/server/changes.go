@@ -0,0 +1,51 @@
+package server
+
+// maxPayloadCommits is the number of commits push payloads list at most
+const maxPayloadCommits = 20
+
+// changeSet collects distinct files changed by commits
+type changeSet struct {
+	seen    map[string]bool
+	files   []string
+	commits int
+}
+
+func newChangeSet() *changeSet {
+	return &changeSet{
+		seen:  make(map[string]bool),
+		files: make([]string, 0),
+	}
+}
+
+// addCommit adds files changed by a commit of the push
+func (set *changeSet) addCommit(added, modified, removed []string) {
+	set.commits++
+	set.add(added...)
+	set.add(modified...)
+	set.add(removed...)
+}
+
+func (set *changeSet) add(files ...string) {
+	for _, file := range files {
+		if !set.seen[file] {
+			set.seen[file] = true
+			set.files = append(set.files, file)
+		}
+	}
+}
+
+// list returns changed files or nil when they are unknown. Changes are not applicable to a tag,
+// pushes without commits (ex. force pushes or new branches) do not list them and payloads of
+// pushes with more commits than listed are truncated. Total is the number of commits of the push
+// or 0 if the payload does not tell, then payloads listing maxPayloadCommits are truncated.
+func (set *changeSet) list(tag string, total int) []string {
+	if tag != "" || set.commits == 0 {
+		return nil
+	}
+
+	if set.commits < total || (total == 0 && set.commits >= maxPayloadCommits) {
+		return nil
+	}
+
+	return set.files
+}
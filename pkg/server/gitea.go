@@ -0,0 +1,250 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"gitlab.com/pongsatt/githook/pkg/tekton"
+)
+
+const (
+	giteaHeaderEvent     = "Gitea-Event"
+	giteaHeaderDelivery  = "Gitea-Delivery"
+	giteaHeaderSignature = "X-Gitea-Signature"
+)
+
+// GiteaServer provides gitea git functionalities
+type GiteaServer struct {
+	secretToken string
+}
+
+// NewGiteaServer creates new gitea provider
+func NewGiteaServer(secretToken string) (*GiteaServer, error) {
+	return &GiteaServer{secretToken}, nil
+}
+
+// GetEventHeader returns gitea event header
+func (git *GiteaServer) GetEventHeader() string {
+	return giteaHeaderEvent
+}
+
+// GetDeliveryHeader returns gitea delivery header
+func (git *GiteaServer) GetDeliveryHeader() string {
+	return giteaHeaderDelivery
+}
+
+// newGiteaPayload returns payload to decode event into or nil if event is not supported
+func newGiteaPayload(event string) interface{} {
+	switch event {
+	case "create":
+		return &GiteaCreatePayload{}
+	case "delete":
+		return &GiteaDeletePayload{}
+	case "fork":
+		return &GiteaForkPayload{}
+	case "push":
+		return &GiteaPushPayload{}
+	case "issues":
+		return &GiteaIssuesPayload{}
+	case "issue_comment":
+		return &GiteaIssueCommentPayload{}
+	case "pull_request", "pull_request_approved", "pull_request_rejected", "pull_request_comment":
+		return &GiteaPullRequestPayload{}
+	case "release":
+		return &GiteaReleasePayload{}
+	}
+
+	return nil
+}
+
+// Parse returns gitea payload
+func (git *GiteaServer) Parse(r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost {
+		return nil, fmt.Errorf("invalid HTTP Method")
+	}
+
+	event := r.Header.Get("X-" + giteaHeaderEvent)
+	if event == "" {
+		return nil, fmt.Errorf("missing X-%s Header", giteaHeaderEvent)
+	}
+
+	if err := verifySignature(r, giteaHeaderSignature, git.secretToken); err != nil {
+		return nil, err
+	}
+
+	payload := newGiteaPayload(event)
+	if payload == nil {
+		return nil, fmt.Errorf("event %s not defined to be parsed", event)
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		return nil, fmt.Errorf("error parsing payload")
+	}
+
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func giteaUserName(user *GiteaUser) string {
+	if user == nil {
+		return ""
+	}
+
+	return firstNonEmpty(user.UserName, user.Login)
+}
+
+func giteaRepo(repo *GiteaRepository) (gitURL string, owner string, name string, defaultBranch string) {
+	if repo == nil {
+		return "", "", "", ""
+	}
+
+	return repo.HTMLURL, giteaUserName(repo.Owner), repo.Name, repo.DefaultBranch
+}
+
+// giteaDefaultBranchOptions builds options of events which are not related to a git reference
+func giteaDefaultBranchOptions(repo *GiteaRepository, author *GiteaUser, sender *GiteaUser) tekton.PipelineOptions {
+	gitURL, owner, name, defaultBranch := giteaRepo(repo)
+	return tekton.PipelineOptions{
+		GitURL:      gitURL,
+		GitRevision: defaultBranch,
+		GitBranch:   defaultBranch,
+		RepoOwner:   owner,
+		RepoName:    name,
+		Author:      firstNonEmpty(giteaUserName(author), giteaUserName(sender)),
+		Committer:   giteaUserName(sender),
+		Sender:      giteaUserName(sender),
+	}
+}
+
+// BuildOptionFromPayload builds pipeline option from payload information
+func (git *GiteaServer) BuildOptionFromPayload(payload interface{}) tekton.PipelineOptions {
+	switch payload.(type) {
+	case *GiteaCreatePayload:
+		p := payload.(*GiteaCreatePayload)
+		branch, tag := refTypeToBranchOrTag(p.RefType, p.Ref)
+		gitURL, owner, name, _ := giteaRepo(p.Repository)
+		return tekton.PipelineOptions{
+			GitURL:      gitURL,
+			GitRevision: p.Ref,
+			GitCommit:   p.Sha,
+			GitBranch:   branch,
+			GitTag:      tag,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      giteaUserName(p.Sender),
+			Committer:   giteaUserName(p.Sender),
+			Sender:      giteaUserName(p.Sender),
+		}
+	case *GiteaDeletePayload:
+		p := payload.(*GiteaDeletePayload)
+		branch, tag := refTypeToBranchOrTag(p.RefType, p.Ref)
+		gitURL, owner, name, _ := giteaRepo(p.Repository)
+		return tekton.PipelineOptions{
+			GitURL:      gitURL,
+			GitRevision: p.Ref,
+			GitBranch:   branch,
+			GitTag:      tag,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      giteaUserName(p.Sender),
+			Committer:   giteaUserName(p.Sender),
+			Sender:      giteaUserName(p.Sender),
+		}
+	case *GiteaForkPayload:
+		p := payload.(*GiteaForkPayload)
+		return giteaDefaultBranchOptions(p.Repository, nil, p.Sender)
+	case *GiteaPushPayload:
+		p := payload.(*GiteaPushPayload)
+		branch, tag := refToBranchOrTag(p.Ref)
+		gitURL, owner, name, _ := giteaRepo(p.Repository)
+		author := giteaUserName(p.Pusher)
+		committer := author
+		changes := newChangeSet()
+		for _, commit := range p.Commits {
+			changes.addCommit(commit.Added, commit.Modified, commit.Removed)
+
+			if commit.ID == p.After {
+				if commit.Author != nil {
+					author = firstNonEmpty(commit.Author.UserName, commit.Author.Name, author)
+				}
+				if commit.Committer != nil {
+					committer = firstNonEmpty(commit.Committer.UserName, commit.Committer.Name, committer)
+				}
+			}
+		}
+		return tekton.PipelineOptions{
+			GitURL:       gitURL,
+			GitRevision:  p.Ref,
+			GitCommit:    p.After,
+			GitBranch:    branch,
+			GitTag:       tag,
+			RepoOwner:    owner,
+			RepoName:     name,
+			Author:       author,
+			Committer:    committer,
+			Sender:       firstNonEmpty(giteaUserName(p.Sender), giteaUserName(p.Pusher)),
+			ChangedFiles: changes.list(tag, p.TotalCommits),
+		}
+	case *GiteaIssuesPayload:
+		p := payload.(*GiteaIssuesPayload)
+		var author *GiteaUser
+		if p.Issue != nil {
+			author = p.Issue.User
+		}
+		return giteaDefaultBranchOptions(p.Repository, author, p.Sender)
+	case *GiteaIssueCommentPayload:
+		p := payload.(*GiteaIssueCommentPayload)
+		var author *GiteaUser
+		if p.Comment != nil {
+			author = p.Comment.User
+		}
+		return giteaDefaultBranchOptions(p.Repository, author, p.Sender)
+	case *GiteaPullRequestPayload:
+		p := payload.(*GiteaPullRequestPayload)
+		gitURL, owner, name, _ := giteaRepo(p.Repository)
+		options := tekton.PipelineOptions{
+			GitURL:            gitURL,
+			RepoOwner:         owner,
+			RepoName:          name,
+			Committer:         giteaUserName(p.Sender),
+			Sender:            giteaUserName(p.Sender),
+			PullRequestNumber: int(p.Number),
+		}
+		if pr := p.PullRequest; pr != nil {
+			options.Author = giteaUserName(pr.User)
+			if pr.Head != nil {
+				options.GitRevision = pr.Head.Ref
+				options.GitCommit = pr.Head.Sha
+				options.GitBranch = pr.Head.Ref
+				options.SourceBranch = pr.Head.Ref
+			}
+			if pr.Base != nil {
+				options.TargetBranch = pr.Base.Ref
+			}
+		}
+		return options
+	case *GiteaReleasePayload:
+		p := payload.(*GiteaReleasePayload)
+		gitURL, owner, name, _ := giteaRepo(p.Repository)
+		options := tekton.PipelineOptions{
+			GitURL:    gitURL,
+			RepoOwner: owner,
+			RepoName:  name,
+			Committer: giteaUserName(p.Sender),
+			Sender:    giteaUserName(p.Sender),
+		}
+		if p.Release != nil {
+			options.GitRevision = p.Release.TargetCommitish
+			options.GitTag = p.Release.TagName
+			options.Author = giteaUserName(p.Release.Author)
+		}
+		return options
+	}
+	return tekton.PipelineOptions{}
+}
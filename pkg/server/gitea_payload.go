@@ -0,0 +1,165 @@
+package server
+
+// GiteaUser is a gitea user in webhook payloads
+type GiteaUser struct {
+	ID       int64  `json:"id"`
+	Login    string `json:"login"`
+	UserName string `json:"username"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
+// GiteaRepository is a gitea repository in webhook payloads
+type GiteaRepository struct {
+	ID            int64      `json:"id"`
+	Owner         *GiteaUser `json:"owner"`
+	Name          string     `json:"name"`
+	FullName      string     `json:"full_name"`
+	HTMLURL       string     `json:"html_url"`
+	CloneURL      string     `json:"clone_url"`
+	DefaultBranch string     `json:"default_branch"`
+}
+
+// GiteaCommitUser is author or committer of a commit
+type GiteaCommitUser struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	UserName string `json:"username"`
+}
+
+// GiteaCommit is a commit in push payload
+type GiteaCommit struct {
+	ID        string           `json:"id"`
+	Message   string           `json:"message"`
+	URL       string           `json:"url"`
+	Author    *GiteaCommitUser `json:"author"`
+	Committer *GiteaCommitUser `json:"committer"`
+	Added     []string         `json:"added"`
+	Removed   []string         `json:"removed"`
+	Modified  []string         `json:"modified"`
+}
+
+// GiteaBranch is head or base of a pull request
+type GiteaBranch struct {
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	Sha   string `json:"sha"`
+}
+
+// GiteaPullRequest is a gitea pull request
+type GiteaPullRequest struct {
+	ID     int64        `json:"id"`
+	Number int64        `json:"number"`
+	User   *GiteaUser   `json:"user"`
+	Title  string       `json:"title"`
+	State  string       `json:"state"`
+	Head   *GiteaBranch `json:"head"`
+	Base   *GiteaBranch `json:"base"`
+}
+
+// GiteaIssue is a gitea issue
+type GiteaIssue struct {
+	ID     int64      `json:"id"`
+	Number int64      `json:"number"`
+	User   *GiteaUser `json:"user"`
+	Title  string     `json:"title"`
+	State  string     `json:"state"`
+}
+
+// GiteaComment is a comment of issue or pull request
+type GiteaComment struct {
+	ID   int64      `json:"id"`
+	User *GiteaUser `json:"user"`
+	Body string     `json:"body"`
+}
+
+// GiteaReview is review information of pull request review events
+type GiteaReview struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// GiteaRelease is a gitea release
+type GiteaRelease struct {
+	ID              int64      `json:"id"`
+	TagName         string     `json:"tag_name"`
+	TargetCommitish string     `json:"target_commitish"`
+	Name            string     `json:"name"`
+	Author          *GiteaUser `json:"author"`
+}
+
+// GiteaCreatePayload is the gitea create event payload
+type GiteaCreatePayload struct {
+	Sha        string           `json:"sha"`
+	Ref        string           `json:"ref"`
+	RefType    string           `json:"ref_type"`
+	Repository *GiteaRepository `json:"repository"`
+	Sender     *GiteaUser       `json:"sender"`
+}
+
+// GiteaDeletePayload is the gitea delete event payload
+type GiteaDeletePayload struct {
+	Ref        string           `json:"ref"`
+	RefType    string           `json:"ref_type"`
+	Repository *GiteaRepository `json:"repository"`
+	Sender     *GiteaUser       `json:"sender"`
+}
+
+// GiteaForkPayload is the gitea fork event payload
+type GiteaForkPayload struct {
+	Forkee     *GiteaRepository `json:"forkee"`
+	Repository *GiteaRepository `json:"repository"`
+	Sender     *GiteaUser       `json:"sender"`
+}
+
+// GiteaPushPayload is the gitea push event payload
+type GiteaPushPayload struct {
+	Ref          string           `json:"ref"`
+	Before       string           `json:"before"`
+	After        string           `json:"after"`
+	CompareURL   string           `json:"compare_url"`
+	Commits      []*GiteaCommit   `json:"commits"`
+	TotalCommits int              `json:"total_commits"`
+	HeadCommit   *GiteaCommit     `json:"head_commit"`
+	Repository   *GiteaRepository `json:"repository"`
+	Pusher       *GiteaUser       `json:"pusher"`
+	Sender       *GiteaUser       `json:"sender"`
+}
+
+// GiteaIssuesPayload is the gitea issues event payload
+type GiteaIssuesPayload struct {
+	Action     string           `json:"action"`
+	Number     int64            `json:"number"`
+	Issue      *GiteaIssue      `json:"issue"`
+	Repository *GiteaRepository `json:"repository"`
+	Sender     *GiteaUser       `json:"sender"`
+}
+
+// GiteaIssueCommentPayload is the gitea issue_comment event payload
+type GiteaIssueCommentPayload struct {
+	Action     string           `json:"action"`
+	Issue      *GiteaIssue      `json:"issue"`
+	Comment    *GiteaComment    `json:"comment"`
+	IsPull     bool             `json:"is_pull"`
+	Repository *GiteaRepository `json:"repository"`
+	Sender     *GiteaUser       `json:"sender"`
+}
+
+// GiteaPullRequestPayload is the gitea pull_request event payload. It is also sent
+// for pull request review events with review information.
+type GiteaPullRequestPayload struct {
+	Action      string            `json:"action"`
+	Number      int64             `json:"number"`
+	PullRequest *GiteaPullRequest `json:"pull_request"`
+	Review      *GiteaReview      `json:"review"`
+	Repository  *GiteaRepository  `json:"repository"`
+	Sender      *GiteaUser        `json:"sender"`
+}
+
+// GiteaReleasePayload is the gitea release event payload
+type GiteaReleasePayload struct {
+	Action     string           `json:"action"`
+	Release    *GiteaRelease    `json:"release"`
+	Repository *GiteaRepository `json:"repository"`
+	Sender     *GiteaUser       `json:"sender"`
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newGiteaRequest(event, body, signature string) *http.Request {
+	r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("X-Gitea-Event", event)
+	r.Header.Set("X-Gitea-Signature", signature)
+	return r
+}
+
+func TestGiteaParsePullRequestReview(t *testing.T) {
+	body := `{
+		"action": "reviewed",
+		"number": 3,
+		"pull_request": {
+			"number": 3,
+			"user": {"login": "alice", "username": "alice"},
+			"head": {"ref": "feature/login", "sha": "abc123"},
+			"base": {"ref": "master", "sha": "def456"}
+		},
+		"review": {"type": "pull_request_review_approved", "content": "lgtm"},
+		"repository": {"name": "app", "html_url": "https://gitea.example.com/team/app", "owner": {"login": "team", "username": "team"}},
+		"sender": {"login": "bob", "username": "bob"}
+	}`
+
+	git, _ := NewGiteaServer("secret")
+
+	payload, err := git.Parse(newGiteaRequest("pull_request_approved", body, sign(sha256.New, "secret", body)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if review := payload.(*GiteaPullRequestPayload).Review; review == nil || review.Type != "pull_request_review_approved" {
+		t.Fatalf("expected review to be parsed but got %v", review)
+	}
+
+	options := git.BuildOptionFromPayload(payload)
+
+	if options.GitBranch != "feature/login" || options.GitCommit != "abc123" || options.TargetBranch != "master" {
+		t.Fatalf("unexpected git information %+v", options)
+	}
+
+	if options.RepoOwner != "team" || options.RepoName != "app" || options.Author != "alice" || options.Committer != "bob" || options.Sender != "bob" || options.PullRequestNumber != 3 {
+		t.Fatalf("unexpected event information %+v", options)
+	}
+}
+
+func TestGiteaParseInvalid(t *testing.T) {
+	body := `{"ref": "refs/heads/master"}`
+	git, _ := NewGiteaServer("secret")
+
+	tests := []struct {
+		event     string
+		signature string
+	}{
+		{event: "push", signature: sign(sha256.New, "other", body)},
+		{event: "repository", signature: sign(sha256.New, "secret", body)},
+		{event: "", signature: sign(sha256.New, "secret", body)},
+	}
+
+	for _, test := range tests {
+		if _, err := git.Parse(newGiteaRequest(test.event, body, test.signature)); err == nil {
+			t.Fatalf("expected error for event %q with signature %s", test.event, test.signature)
+		}
+	}
+}
@@ -8,7 +8,8 @@ import (
 )
 
 const (
-	githubHeaderEvent = "GitHub-Event"
+	githubHeaderEvent    = "GitHub-Event"
+	githubHeaderDelivery = "GitHub-Delivery"
 )
 
 // GithubServer provides github git functionalities
@@ -42,7 +43,18 @@ func (git *GithubServer) Parse(r *http.Request) (interface{}, error) {
 		github.IssuesEvent,
 		github.IssueCommentEvent,
 		github.PullRequestEvent,
-		github.ReleaseEvent)
+		github.PullRequestReviewEvent,
+		github.PullRequestReviewCommentEvent,
+		github.ReleaseEvent,
+		github.GollumEvent,
+		github.DeploymentEvent,
+		github.StatusEvent,
+		github.MemberEvent)
+}
+
+// GetDeliveryHeader returns github delivery header
+func (git *GithubServer) GetDeliveryHeader() string {
+	return githubHeaderDelivery
 }
 
 // BuildOptionFromPayload builds pipeline option from payload information
@@ -50,52 +62,203 @@ func (git *GithubServer) BuildOptionFromPayload(payload interface{}) tekton.Pipe
 	switch payload.(type) {
 	case github.CreatePayload:
 		p := payload.(github.CreatePayload)
+		branch, tag := refTypeToBranchOrTag(p.RefType, p.Ref)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Ref,
+			GitBranch:   branch,
+			GitTag:      tag,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Sender.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
 		}
 	case github.ReleasePayload:
 		p := payload.(github.ReleasePayload)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Release.TargetCommitish,
+			GitTag:      p.Release.TagName,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Release.Author.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
 		}
 	case github.PushPayload:
 		p := payload.(github.PushPayload)
+		branch, tag := refToBranchOrTag(p.Ref)
+		changes := newChangeSet()
+		for _, commit := range p.Commits {
+			changes.addCommit(commit.Added, commit.Modified, commit.Removed)
+		}
 		return tekton.PipelineOptions{
-			GitURL:      p.Repository.HTMLURL,
-			GitRevision: p.Ref,
-			GitCommit:   p.After,
+			GitURL:       p.Repository.HTMLURL,
+			GitRevision:  p.Ref,
+			GitCommit:    p.After,
+			GitBranch:    branch,
+			GitTag:       tag,
+			RepoOwner:    p.Repository.Owner.Login,
+			RepoName:     p.Repository.Name,
+			Author:       firstNonEmpty(p.HeadCommit.Author.Username, p.HeadCommit.Author.Name, p.Sender.Login),
+			Committer:    firstNonEmpty(p.HeadCommit.Committer.Username, p.HeadCommit.Committer.Name, p.Sender.Login),
+			Sender:       p.Sender.Login,
+			ChangedFiles: changes.list(tag, 0),
 		}
 	case github.DeletePayload:
 		p := payload.(github.DeletePayload)
+		branch, tag := refTypeToBranchOrTag(p.RefType, p.Ref)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Ref,
+			GitBranch:   branch,
+			GitTag:      tag,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Sender.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
 		}
 	case github.ForkPayload:
 		p := payload.(github.ForkPayload)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Repository.DefaultBranch,
+			GitBranch:   p.Repository.DefaultBranch,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Sender.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
 		}
 	case github.IssuesPayload:
 		p := payload.(github.IssuesPayload)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Repository.DefaultBranch,
+			GitBranch:   p.Repository.DefaultBranch,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Issue.User.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
 		}
 	case github.IssueCommentPayload:
 		p := payload.(github.IssueCommentPayload)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Repository.DefaultBranch,
+			GitBranch:   p.Repository.DefaultBranch,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Comment.User.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
 		}
 	case github.PullRequestPayload:
 		p := payload.(github.PullRequestPayload)
+		return tekton.PipelineOptions{
+			GitURL:            p.Repository.HTMLURL,
+			GitRevision:       p.PullRequest.Head.Ref,
+			GitCommit:         p.PullRequest.Head.Sha,
+			GitBranch:         p.PullRequest.Head.Ref,
+			RepoOwner:         p.Repository.Owner.Login,
+			RepoName:          p.Repository.Name,
+			Author:            p.PullRequest.User.Login,
+			Committer:         p.Sender.Login,
+			Sender:            p.Sender.Login,
+			PullRequestNumber: int(p.Number),
+			SourceBranch:      p.PullRequest.Head.Ref,
+			TargetBranch:      p.PullRequest.Base.Ref,
+		}
+	case github.PullRequestReviewPayload:
+		p := payload.(github.PullRequestReviewPayload)
+		return tekton.PipelineOptions{
+			GitURL:            p.Repository.HTMLURL,
+			GitRevision:       p.PullRequest.Head.Ref,
+			GitCommit:         p.PullRequest.Head.Sha,
+			GitBranch:         p.PullRequest.Head.Ref,
+			RepoOwner:         p.Repository.Owner.Login,
+			RepoName:          p.Repository.Name,
+			Author:            p.Review.User.Login,
+			Committer:         p.Sender.Login,
+			Sender:            p.Sender.Login,
+			PullRequestNumber: int(p.PullRequest.Number),
+			SourceBranch:      p.PullRequest.Head.Ref,
+			TargetBranch:      p.PullRequest.Base.Ref,
+		}
+	case github.PullRequestReviewCommentPayload:
+		p := payload.(github.PullRequestReviewCommentPayload)
+		return tekton.PipelineOptions{
+			GitURL:            p.Repository.HTMLURL,
+			GitRevision:       p.PullRequest.Head.Ref,
+			GitCommit:         p.PullRequest.Head.Sha,
+			GitBranch:         p.PullRequest.Head.Ref,
+			RepoOwner:         p.Repository.Owner.Login,
+			RepoName:          p.Repository.Name,
+			Author:            p.Comment.User.Login,
+			Committer:         p.Sender.Login,
+			Sender:            p.Sender.Login,
+			PullRequestNumber: int(p.PullRequest.Number),
+			SourceBranch:      p.PullRequest.Head.Ref,
+			TargetBranch:      p.PullRequest.Base.Ref,
+		}
+	case github.GollumPayload:
+		p := payload.(github.GollumPayload)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
-			GitRevision: p.PullRequest.Head.Ref,
+			GitRevision: p.Repository.DefaultBranch,
+			GitBranch:   p.Repository.DefaultBranch,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Sender.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
+		}
+	case github.DeploymentPayload:
+		p := payload.(github.DeploymentPayload)
+		branch, tag := refToBranchOrTag(p.Deployment.Ref)
+		return tekton.PipelineOptions{
+			GitURL:      p.Repository.HTMLURL,
+			GitRevision: p.Deployment.Ref,
+			GitCommit:   p.Deployment.Sha,
+			GitBranch:   branch,
+			GitTag:      tag,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Deployment.Creator.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
+		}
+	case github.StatusPayload:
+		p := payload.(github.StatusPayload)
+		options := tekton.PipelineOptions{
+			GitURL:      p.Repository.HTMLURL,
+			GitRevision: p.Sha,
+			GitCommit:   p.Sha,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Sender.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
+		}
+		// a commit may be on several branches, the first one is used
+		if len(p.Branches) > 0 {
+			options.GitBranch = p.Branches[0].Name
+		}
+		return options
+	case github.MemberPayload:
+		p := payload.(github.MemberPayload)
+		return tekton.PipelineOptions{
+			GitURL:      p.Repository.HTMLURL,
+			GitRevision: p.Repository.DefaultBranch,
+			GitBranch:   p.Repository.DefaultBranch,
+			RepoOwner:   p.Repository.Owner.Login,
+			RepoName:    p.Repository.Name,
+			Author:      p.Member.Login,
+			Committer:   p.Sender.Login,
+			Sender:      p.Sender.Login,
 		}
 	}
 	return tekton.PipelineOptions{}
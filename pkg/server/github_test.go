@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/go-playground/webhooks.v5/github"
+)
+
+// githubPushBody returns push payload of commits changing a file each
+func githubPushBody(commits int) string {
+	list := []string{}
+	for i := 0; i < commits; i++ {
+		list = append(list, fmt.Sprintf(`{"id": "c%d", "modified": ["docs/%d.md"]}`, i, i))
+	}
+
+	return `{
+		"ref": "refs/heads/main",
+		"after": "c0ffee",
+		"sender": {"login": "octocat"},
+		"head_commit": {"committer": {"name": "Mona Lisa"}},
+		"repository": {"name": "app", "html_url": "https://github.com/myorg/app", "owner": {"login": "myorg"}},
+		"commits": [` + strings.Join(list, ",") + `]
+	}`
+}
+
+func TestGithubPushChangedFiles(t *testing.T) {
+	git, _ := NewGithubServer("secret")
+
+	tests := []struct {
+		commits  int
+		expected []string
+	}{
+		{commits: 2, expected: []string{"docs/0.md", "docs/1.md"}},
+		// force pushes and new branches list no commits
+		{commits: 0, expected: nil},
+		// payloads list the first 20 commits of larger pushes
+		{commits: maxPayloadCommits, expected: nil},
+	}
+
+	for _, test := range tests {
+		payload := github.PushPayload{}
+		if err := json.Unmarshal([]byte(githubPushBody(test.commits)), &payload); err != nil {
+			t.Fatal(err)
+		}
+
+		options := git.BuildOptionFromPayload(payload)
+
+		if options.Sender != "octocat" || options.Committer != "Mona Lisa" {
+			t.Fatalf("expected sender octocat and committer Mona Lisa but got %s and %s", options.Sender, options.Committer)
+		}
+
+		if !reflect.DeepEqual(options.ChangedFiles, test.expected) {
+			t.Fatalf("expected changed files %v of push of %d commits but got %v", test.expected, test.commits, options.ChangedFiles)
+		}
+	}
+}
@@ -8,7 +8,8 @@ import (
 )
 
 const (
-	gitlabHeaderEvent = "Gitlab-Event"
+	gitlabHeaderEvent    = "Gitlab-Event"
+	gitlabHeaderDelivery = "Gitlab-Event-UUID"
 )
 
 // GitlabServer provides gitlab git functionalities
@@ -36,9 +37,17 @@ func (git *GitlabServer) GetEventHeader() string {
 func (git *GitlabServer) Parse(r *http.Request) (interface{}, error) {
 	return git.hook.Parse(r,
 		gitlab.PushEvents,
+		gitlab.TagEvents,
 		gitlab.IssuesEvents,
 		gitlab.CommentEvents,
-		gitlab.MergeRequestEvents)
+		gitlab.MergeRequestEvents,
+		gitlab.PipelineEvents,
+		gitlab.WikiPageEvents)
+}
+
+// GetDeliveryHeader returns gitlab delivery header
+func (git *GitlabServer) GetDeliveryHeader() string {
+	return gitlabHeaderDelivery
 }
 
 // BuildOptionFromPayload builds pipeline option from payload information
@@ -46,28 +55,119 @@ func (git *GitlabServer) BuildOptionFromPayload(payload interface{}) tekton.Pipe
 	switch payload.(type) {
 	case gitlab.PushEventPayload:
 		p := payload.(gitlab.PushEventPayload)
+		branch, tag := refToBranchOrTag(p.Ref)
+		owner, name := splitFullName(p.Project.PathWithNamespace)
+		author := p.UserName
+		changes := newChangeSet()
+		for _, commit := range p.Commits {
+			changes.addCommit(commit.Added, commit.Modified, commit.Removed)
+
+			if commit.ID == p.After {
+				author = firstNonEmpty(commit.Author.Name, author)
+			}
+		}
+		return tekton.PipelineOptions{
+			GitURL:       p.Project.HTTPURL,
+			GitRevision:  p.Ref,
+			GitCommit:    p.After,
+			GitBranch:    branch,
+			GitTag:       tag,
+			RepoOwner:    owner,
+			RepoName:     name,
+			Author:       author,
+			Committer:    p.UserName,
+			Sender:       p.UserName,
+			ChangedFiles: changes.list(tag, int(p.TotalCommitsCount)),
+		}
+	case gitlab.TagEventPayload:
+		p := payload.(gitlab.TagEventPayload)
+		_, tag := refToBranchOrTag(p.Ref)
+		owner, name := splitFullName(p.Project.PathWithNamespace)
 		return tekton.PipelineOptions{
 			GitURL:      p.Project.HTTPURL,
 			GitRevision: p.Ref,
-			GitCommit:   p.After,
+			GitCommit:   p.CheckoutSHA,
+			GitTag:      tag,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      p.UserName,
+			Committer:   p.UserName,
+			Sender:      p.UserName,
 		}
 	case gitlab.IssueEventPayload:
 		p := payload.(gitlab.IssueEventPayload)
+		owner, name := splitFullName(p.Project.PathWithNamespace)
 		return tekton.PipelineOptions{
 			GitURL:      p.Project.HTTPURL,
 			GitRevision: p.Project.DefaultBranch,
+			GitBranch:   p.Project.DefaultBranch,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      p.User.UserName,
+			Committer:   p.User.UserName,
+			Sender:      p.User.UserName,
 		}
 	case gitlab.CommentEventPayload:
 		p := payload.(gitlab.CommentEventPayload)
+		owner, name := splitFullName(p.Project.PathWithNamespace)
 		return tekton.PipelineOptions{
 			GitURL:      p.Project.HTTPURL,
 			GitRevision: p.Project.DefaultBranch,
+			GitBranch:   p.Project.DefaultBranch,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      p.User.UserName,
+			Committer:   p.User.UserName,
+			Sender:      p.User.UserName,
 		}
 	case gitlab.MergeRequestEventPayload:
 		p := payload.(gitlab.MergeRequestEventPayload)
+		owner, name := splitFullName(p.Project.PathWithNamespace)
+		return tekton.PipelineOptions{
+			GitURL:            p.Project.HTTPURL,
+			GitRevision:       p.ObjectAttributes.SourceBranch,
+			GitCommit:         p.ObjectAttributes.LastCommit.ID,
+			GitBranch:         p.ObjectAttributes.SourceBranch,
+			RepoOwner:         owner,
+			RepoName:          name,
+			Author:            firstNonEmpty(p.ObjectAttributes.LastCommit.Author.Name, p.User.UserName),
+			Committer:         p.User.UserName,
+			Sender:            p.User.UserName,
+			PullRequestNumber: int(p.ObjectAttributes.IID),
+			SourceBranch:      p.ObjectAttributes.SourceBranch,
+			TargetBranch:      p.ObjectAttributes.TargetBranch,
+		}
+	case gitlab.PipelineEventPayload:
+		p := payload.(gitlab.PipelineEventPayload)
+		owner, name := splitFullName(p.Project.PathWithNamespace)
+		options := tekton.PipelineOptions{
+			GitURL:      p.Project.HTTPURL,
+			GitRevision: p.ObjectAttributes.Ref,
+			GitCommit:   p.ObjectAttributes.SHA,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      p.User.UserName,
+			Committer:   p.User.UserName,
+			Sender:      p.User.UserName,
+		}
+		if p.ObjectAttributes.Tag {
+			options.GitTag = p.ObjectAttributes.Ref
+		} else {
+			options.GitBranch = p.ObjectAttributes.Ref
+		}
+		return options
+	case gitlab.WikiPageEventPayload:
+		p := payload.(gitlab.WikiPageEventPayload)
+		owner, name := splitFullName(p.Project.PathWithNamespace)
 		return tekton.PipelineOptions{
 			GitURL:      p.Project.HTTPURL,
-			GitRevision: p.ObjectAttributes.SourceBranch,
+			GitRevision: p.Project.DefaultBranch,
+			GitBranch:   p.Project.DefaultBranch,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      p.User.UserName,
+			Committer:   p.User.UserName,
+			Sender:      p.User.UserName,
 		}
 	}
 	return tekton.PipelineOptions{}
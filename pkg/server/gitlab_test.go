@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const gitlabTagPushBody = `{
+	"object_kind": "tag_push",
+	"ref": "refs/tags/v1.0.0",
+	"checkout_sha": "82b3d5ae55f7080f1e6022629cdb57bfae7cccc7",
+	"user_name": "alice",
+	"project": {"path_with_namespace": "mygroup/app", "http_url": "https://gitlab.example.com/mygroup/app.git"}
+}`
+
+const gitlabPipelineBody = `{
+	"object_kind": "pipeline",
+	"object_attributes": {"ref": "main", "tag": false, "sha": "bcbb5ec396a2c0f828686f14fac9b80b780504f2", "status": "success"},
+	"user": {"username": "bob"},
+	"project": {"path_with_namespace": "mygroup/app", "http_url": "https://gitlab.example.com/mygroup/app.git"}
+}`
+
+func TestGitlabParse(t *testing.T) {
+	git, _ := NewGitlabServer("secret")
+
+	tests := []struct {
+		event  string
+		body   string
+		branch string
+		tag    string
+		commit string
+	}{
+		{event: "Tag Push Hook", body: gitlabTagPushBody, tag: "v1.0.0", commit: "82b3d5ae55f7080f1e6022629cdb57bfae7cccc7"},
+		{event: "Pipeline Hook", body: gitlabPipelineBody, branch: "main", commit: "bcbb5ec396a2c0f828686f14fac9b80b780504f2"},
+	}
+
+	for _, test := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+		r.Header.Set("X-Gitlab-Event", test.event)
+		r.Header.Set("X-Gitlab-Token", "secret")
+
+		payload, err := git.Parse(r)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %s: %s", test.event, err)
+		}
+
+		options := git.BuildOptionFromPayload(payload)
+
+		if options.GitBranch != test.branch || options.GitTag != test.tag || options.GitCommit != test.commit {
+			t.Fatalf("expected branch %q tag %q commit %q of %s but got %+v", test.branch, test.tag, test.commit, test.event, options)
+		}
+
+		if options.RepoOwner != "mygroup" || options.RepoName != "app" {
+			t.Fatalf("expected repository mygroup/app of %s but got %s/%s", test.event, options.RepoOwner, options.RepoName)
+		}
+	}
+}
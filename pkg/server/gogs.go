@@ -9,7 +9,8 @@ import (
 )
 
 const (
-	gogsHeaderEvent = "Gogs-Event"
+	gogsHeaderEvent    = "Gogs-Event"
+	gogsHeaderDelivery = "Gogs-Delivery"
 )
 
 // GogsServer provides gogs git functionalities
@@ -46,57 +47,159 @@ func (git *GogsServer) Parse(r *http.Request) (interface{}, error) {
 		gogs.ReleaseEvent)
 }
 
+// GetDeliveryHeader returns gogs delivery header
+func (git *GogsServer) GetDeliveryHeader() string {
+	return gogsHeaderDelivery
+}
+
+func gogsUserName(user *gogsclient.User) string {
+	if user == nil {
+		return ""
+	}
+
+	return firstNonEmpty(user.UserName, user.Login)
+}
+
+func gogsRepoOwnerAndName(repo *gogsclient.Repository) (owner string, name string) {
+	if repo == nil {
+		return "", ""
+	}
+
+	return gogsUserName(repo.Owner), repo.Name
+}
+
 // BuildOptionFromPayload builds pipeline option from payload information
 func (git *GogsServer) BuildOptionFromPayload(payload interface{}) tekton.PipelineOptions {
 	switch payload.(type) {
 	case gogsclient.CreatePayload:
 		p := payload.(gogsclient.CreatePayload)
+		branch, tag := refTypeToBranchOrTag(p.RefType, p.Ref)
+		owner, name := gogsRepoOwnerAndName(p.Repo)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repo.HTMLURL,
 			GitRevision: p.Ref,
+			GitBranch:   branch,
+			GitTag:      tag,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      gogsUserName(p.Sender),
+			Committer:   gogsUserName(p.Sender),
+			Sender:      gogsUserName(p.Sender),
 		}
 	case gogsclient.ReleasePayload:
 		p := payload.(gogsclient.ReleasePayload)
+		owner, name := gogsRepoOwnerAndName(p.Repository)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Release.TargetCommitish,
+			GitTag:      p.Release.TagName,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      gogsUserName(p.Release.Author),
+			Committer:   gogsUserName(p.Sender),
+			Sender:      gogsUserName(p.Sender),
 		}
 	case gogsclient.PushPayload:
 		p := payload.(gogsclient.PushPayload)
+		branch, tag := refToBranchOrTag(p.Ref)
+		owner, name := gogsRepoOwnerAndName(p.Repo)
+		author := gogsUserName(p.Pusher)
+		committer := author
+		changes := newChangeSet()
+		for _, commit := range p.Commits {
+			changes.addCommit(commit.Added, commit.Modified, commit.Removed)
+
+			if commit.ID == p.After {
+				if commit.Author != nil {
+					author = firstNonEmpty(commit.Author.UserName, commit.Author.Name, author)
+				}
+				if commit.Committer != nil {
+					committer = firstNonEmpty(commit.Committer.UserName, commit.Committer.Name, committer)
+				}
+			}
+		}
 		return tekton.PipelineOptions{
-			GitURL:      p.Repo.HTMLURL,
-			GitRevision: p.Ref,
-			GitCommit:   p.After,
+			GitURL:       p.Repo.HTMLURL,
+			GitRevision:  p.Ref,
+			GitCommit:    p.After,
+			GitBranch:    branch,
+			GitTag:       tag,
+			RepoOwner:    owner,
+			RepoName:     name,
+			Author:       author,
+			Committer:    committer,
+			Sender:       gogsUserName(p.Sender),
+			ChangedFiles: changes.list(tag, 0),
 		}
 	case gogsclient.DeletePayload:
 		p := payload.(gogsclient.DeletePayload)
+		branch, tag := refTypeToBranchOrTag(p.RefType, p.Ref)
+		owner, name := gogsRepoOwnerAndName(p.Repo)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repo.HTMLURL,
 			GitRevision: p.Ref,
+			GitBranch:   branch,
+			GitTag:      tag,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      gogsUserName(p.Sender),
+			Committer:   gogsUserName(p.Sender),
+			Sender:      gogsUserName(p.Sender),
 		}
 	case gogsclient.ForkPayload:
 		p := payload.(gogsclient.ForkPayload)
+		owner, name := gogsRepoOwnerAndName(p.Repo)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repo.HTMLURL,
 			GitRevision: p.Repo.DefaultBranch,
+			GitBranch:   p.Repo.DefaultBranch,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      gogsUserName(p.Sender),
+			Committer:   gogsUserName(p.Sender),
+			Sender:      gogsUserName(p.Sender),
 		}
 	case gogsclient.IssuesPayload:
 		p := payload.(gogsclient.IssuesPayload)
+		owner, name := gogsRepoOwnerAndName(p.Repository)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Repository.DefaultBranch,
+			GitBranch:   p.Repository.DefaultBranch,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      gogsUserName(p.Sender),
+			Committer:   gogsUserName(p.Sender),
+			Sender:      gogsUserName(p.Sender),
 		}
 	case gogsclient.IssueCommentPayload:
 		p := payload.(gogsclient.IssueCommentPayload)
+		owner, name := gogsRepoOwnerAndName(p.Repository)
 		return tekton.PipelineOptions{
 			GitURL:      p.Repository.HTMLURL,
 			GitRevision: p.Repository.DefaultBranch,
+			GitBranch:   p.Repository.DefaultBranch,
+			RepoOwner:   owner,
+			RepoName:    name,
+			Author:      gogsUserName(p.Sender),
+			Committer:   gogsUserName(p.Sender),
+			Sender:      gogsUserName(p.Sender),
 		}
 	case gogsclient.PullRequestPayload:
 		p := payload.(gogsclient.PullRequestPayload)
+		owner, name := gogsRepoOwnerAndName(p.Repository)
 		return tekton.PipelineOptions{
-			GitURL:      p.Repository.HTMLURL,
-			GitRevision: p.PullRequest.HeadBranch,
+			GitURL:            p.Repository.HTMLURL,
+			GitRevision:       p.PullRequest.HeadBranch,
+			GitBranch:         p.PullRequest.HeadBranch,
+			RepoOwner:         owner,
+			RepoName:          name,
+			Author:            gogsUserName(p.PullRequest.Poster),
+			Committer:         gogsUserName(p.Sender),
+			Sender:            gogsUserName(p.Sender),
+			PullRequestNumber: int(p.Index),
+			SourceBranch:      p.PullRequest.HeadBranch,
+			TargetBranch:      p.PullRequest.BaseBranch,
 		}
 	}
 	return tekton.PipelineOptions{}
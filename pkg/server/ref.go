@@ -0,0 +1,47 @@
+package server
+
+import "strings"
+
+const (
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+)
+
+// refToBranchOrTag splits a full git reference into branch or tag name
+func refToBranchOrTag(ref string) (branch string, tag string) {
+	if strings.HasPrefix(ref, tagRefPrefix) {
+		return "", strings.TrimPrefix(ref, tagRefPrefix)
+	}
+
+	return strings.TrimPrefix(ref, branchRefPrefix), ""
+}
+
+// refTypeToBranchOrTag returns branch or tag name based on reference type of create and delete events
+func refTypeToBranchOrTag(refType string, ref string) (branch string, tag string) {
+	if refType == "tag" {
+		return "", ref
+	}
+
+	return ref, ""
+}
+
+// firstNonEmpty returns the first value which is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// splitFullName splits full project path into owner and name
+func splitFullName(fullName string) (owner string, name string) {
+	i := strings.LastIndex(fullName, "/")
+	if i < 0 {
+		return "", fullName
+	}
+
+	return fullName[:i], fullName[i+1:]
+}
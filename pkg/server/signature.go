@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// ErrSignatureVerificationFailed is returned when payload signature does not match the secret token
+var ErrSignatureVerificationFailed = errors.New("HMAC verification failed")
+
+// verifySignature verifies hex encoded HMAC signature of request body. Signature may be
+// prefixed with its algorithm (sha1= or sha256=), sha256 is used when no prefix is given.
+// Request body is restored so it can be parsed afterward.
+func verifySignature(r *http.Request, header string, secretToken string) error {
+	signature := r.Header.Get(header)
+	if signature == "" {
+		return errors.New("missing " + header + " header")
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	hashFunc := sha256.New
+	if i := strings.Index(signature, "="); i >= 0 {
+		switch signature[:i] {
+		case "sha1":
+			hashFunc = func() hash.Hash { return sha1.New() }
+		case "sha256":
+		default:
+			return ErrSignatureVerificationFailed
+		}
+		signature = signature[i+1:]
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return ErrSignatureVerificationFailed
+	}
+
+	mac := hmac.New(hashFunc, []byte(secretToken))
+	mac.Write(body)
+
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrSignatureVerificationFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func sign(hashFunc func() hash.Hash, secret, body string) string {
+	mac := hmac.New(hashFunc, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := `{"ref":"refs/heads/master"}`
+
+	tests := []struct {
+		signature string
+		valid     bool
+	}{
+		{signature: "sha256=" + sign(sha256.New, "secret", body), valid: true},
+		{signature: "sha1=" + sign(sha1.New, "secret", body), valid: true},
+		{signature: sign(sha256.New, "secret", body), valid: true},
+		{signature: "sha256=" + sign(sha256.New, "other", body), valid: false},
+		{signature: "md5=" + sign(sha256.New, "secret", body), valid: false},
+		{signature: "", valid: false},
+	}
+
+	for i, test := range tests {
+		r, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if test.signature != "" {
+			r.Header.Set("X-Hub-Signature", test.signature)
+		}
+
+		err := verifySignature(r, "X-Hub-Signature", "secret")
+
+		if (err == nil) != test.valid {
+			t.Fatalf("case %d: expected valid %v but got error %v", i, test.valid, err)
+		}
+
+		if restored, _ := ioutil.ReadAll(r.Body); test.valid && string(restored) != body {
+			t.Fatalf("case %d: expected body to be restored but got %q", i, restored)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package tekton
+
+const provenancePrefix = "githook.tools.pongzt.com/"
+
+// Annotations of pipelinerun holding the original values of the labels.
+const (
+	// AnnotationGitHookName is the annotation of pipelinerun holding name of the GitHook created it
+	AnnotationGitHookName = LabelGitHookName
+
+	// AnnotationEventType is the annotation of pipelinerun holding type of the event
+	AnnotationEventType = LabelEventType
+
+	// AnnotationRepoOwner is the annotation of pipelinerun holding owner of the repository of the event
+	AnnotationRepoOwner = provenancePrefix + "repo-owner"
+
+	// AnnotationRepoName is the annotation of pipelinerun holding name of the repository of the event
+	AnnotationRepoName = provenancePrefix + "repo-name"
+
+	// AnnotationBranch is the annotation of pipelinerun holding branch of the event
+	AnnotationBranch = LabelBranch
+
+	// AnnotationCommit is the annotation of pipelinerun holding commit sha of the event
+	AnnotationCommit = LabelCommit
+
+	// AnnotationPullRequest is the annotation of pipelinerun holding pull request number of the event
+	AnnotationPullRequest = LabelPullRequest
+
+	// AnnotationDeliveryID is the annotation of pipelinerun holding delivery id of the event
+	AnnotationDeliveryID = LabelDeliveryID
+
+	// AnnotationSender is the annotation of pipelinerun holding user triggered the event
+	AnnotationSender = LabelSender
+
+	// AnnotationTrigger is the annotation of pipelinerun holding name of the GitHook trigger matched the event
+	AnnotationTrigger = LabelTrigger
+)
+
+// pipelineRunAnnotations returns annotations describing the GitHook and event triggered pipelinerun
+func pipelineRunAnnotations(options PipelineOptions) map[string]string {
+	annotations := map[string]string{}
+
+	for key, value := range map[string]string{
+		AnnotationGitHookName: options.Prefix,
+		AnnotationEventType:   options.EventType,
+		AnnotationRepoOwner:   options.RepoOwner,
+		AnnotationRepoName:    options.RepoName,
+		AnnotationBranch:      options.GitBranch,
+		AnnotationCommit:      options.GitCommit,
+		AnnotationPullRequest: pullRequestValue(options.PullRequestNumber),
+		AnnotationDeliveryID:  options.DeliveryID,
+		AnnotationSender:      options.Sender,
+		AnnotationTrigger:     options.Trigger,
+	} {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+
+	return annotations
+}
@@ -0,0 +1,241 @@
+package tekton
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	v1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Params passing the git checkout to tekton.dev/v1beta1 and tekton.dev/v1 pipelineruns
+// since pipelineresources are not available there
+const (
+	// ParamGitURL is the param holding url of the repository of the event
+	ParamGitURL = "git-url"
+
+	// ParamGitRevision is the param holding commit sha of the event, or its revision when unknown
+	ParamGitRevision = "git-revision"
+)
+
+// usesPipelineResources checks if pipelineruns of api version are created from
+// tekton.dev/v1alpha1 runspec with git pipelineresource
+func usesPipelineResources(apiVersion githookv1alpha1.PipelineAPIVersion) bool {
+	return apiVersion == "" || apiVersion == githookv1alpha1.PipelineV1alpha1
+}
+
+// pipelineRunResource returns the resource of pipelineruns of api version
+func pipelineRunResource(apiVersion githookv1alpha1.PipelineAPIVersion) (schema.GroupVersionResource, error) {
+	switch apiVersion {
+	case githookv1alpha1.PipelineV1beta1, githookv1alpha1.PipelineV1:
+		groupVersion, err := schema.ParseGroupVersion(string(apiVersion))
+		if err != nil {
+			return schema.GroupVersionResource{}, err
+		}
+		return groupVersion.WithResource("pipelineruns"), nil
+	}
+
+	return schema.GroupVersionResource{}, fmt.Errorf("pipeline api version %s not supported", apiVersion)
+}
+
+// ServedAPIVersions returns the pipeline api versions whose pipelineruns are served by the cluster
+func (client *Client) ServedAPIVersions() ([]githookv1alpha1.PipelineAPIVersion, error) {
+	groups, err := client.kube.Discovery().ServerGroups()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover api groups: %s", err)
+	}
+
+	served := []githookv1alpha1.PipelineAPIVersion{}
+
+	for _, group := range groups.Groups {
+		if group.Name != v1alpha1.SchemeGroupVersion.Group {
+			continue
+		}
+
+		for _, version := range group.Versions {
+			resources, err := client.kube.Discovery().ServerResourcesForGroupVersion(version.GroupVersion)
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to discover resources of %s: %s", version.GroupVersion, err)
+			}
+
+			for _, resource := range resources.APIResources {
+				if resource.Name == "pipelineruns" {
+					served = append(served, githookv1alpha1.PipelineAPIVersion(version.GroupVersion))
+				}
+			}
+		}
+	}
+
+	return served, nil
+}
+
+// cancelledSpecStatus returns the spec status cancelling pipelinerun of api version.
+// tekton.dev/v1beta1 still accepts the deprecated status known by older tekton releases.
+func cancelledSpecStatus(apiVersion string) string {
+	if apiVersion == string(githookv1alpha1.PipelineV1) {
+		return "Cancelled"
+	}
+
+	return v1alpha1.PipelineRunSpecStatusCancelled
+}
+
+// gitParams returns params of the git checkout of the event
+func gitParams(options PipelineOptions) []v1alpha1.Param {
+	revision := options.GitCommit
+	if revision == "" {
+		revision = options.GitRevision
+	}
+
+	return []v1alpha1.Param{
+		{Name: ParamGitURL, Value: options.GitURL},
+		{Name: ParamGitRevision, Value: revision},
+	}
+}
+
+// mergeUnstructuredParams appends params to params of unstructured pipelinerun spec
+// replacing existing params with the same name
+func mergeUnstructuredParams(existing []interface{}, params []v1alpha1.Param) []interface{} {
+	merged := make([]interface{}, 0, len(existing)+len(params))
+	replaced := make(map[string]bool)
+
+	for _, param := range params {
+		replaced[param.Name] = true
+	}
+
+	for _, param := range existing {
+		if fields, ok := param.(map[string]interface{}); ok && replaced[fmt.Sprint(fields["name"])] {
+			continue
+		}
+		merged = append(merged, param)
+	}
+
+	for _, param := range params {
+		merged = append(merged, map[string]interface{}{
+			"name":  param.Name,
+			"value": param.Value,
+		})
+	}
+
+	return merged
+}
+
+// paramNames returns names of params of unstructured pipelinerun spec
+func paramNames(params []interface{}) map[string]bool {
+	names := map[string]bool{}
+
+	for _, param := range params {
+		if fields, ok := param.(map[string]interface{}); ok {
+			names[fmt.Sprint(fields["name"])] = true
+		}
+	}
+
+	return names
+}
+
+// generateUnstructuredPipelineRun builds tekton.dev/v1beta1 or tekton.dev/v1 pipelinerun
+// from runspec passing the git checkout as params
+func generateUnstructuredPipelineRun(options PipelineOptions) (*unstructured.Unstructured, error) {
+	runSpecJSON, err := renderRunSpec(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(runSpecJSON), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse pipelinerun spec: %s", err)
+	}
+
+	existing, _, err := unstructured.NestedSlice(spec, "params")
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid params of pipelinerun spec: %s", err)
+	}
+
+	// params given in runspec take precedence over git params
+	given := paramNames(existing)
+	defaults := []v1alpha1.Param{}
+	for _, param := range gitParams(options) {
+		if !given[param.Name] {
+			defaults = append(defaults, param)
+		}
+	}
+
+	params, err := buildParams(options.Params, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	spec["params"] = mergeUnstructuredParams(mergeUnstructuredParams(existing, defaults), params)
+
+	pipelineRun := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": string(options.APIVersion),
+			"kind":       "PipelineRun",
+			"spec":       spec,
+		},
+	}
+	pipelineRun.SetGenerateName(fmt.Sprintf("%s-", options.Prefix))
+	pipelineRun.SetNamespace(options.Namespace)
+	pipelineRun.SetLabels(pipelineRunLabels(options))
+	pipelineRun.SetAnnotations(pipelineRunAnnotations(options))
+
+	return pipelineRun, nil
+}
+
+// createUnstructuredPipelineRun creates tekton.dev/v1beta1 or tekton.dev/v1 pipelinerun
+// applying the concurrency policy and workspace templates of options.
+// It returns true if the pipelinerun is queued to be created when running ones finish.
+func (client *Client) createUnstructuredPipelineRun(options PipelineOptions) (*unstructured.Unstructured, bool, error) {
+	resource, err := pipelineRunResource(options.APIVersion)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	pipelineRun, err := generateUnstructuredPipelineRun(options)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	claims, err := client.createWorkspaces(options, pipelineRun)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	queued, err := client.queueWhileRunning(options.ConcurrencyPolicy, options.APIVersion, pipelineRun)
+
+	if err != nil && !queued {
+		client.deleteClaims(claims)
+	}
+
+	if err != nil || queued {
+		return pipelineRun, queued, err
+	}
+
+	created, err := client.dynamic.Resource(resource).Namespace(options.Namespace).Create(pipelineRun, metav1.CreateOptions{})
+
+	if err != nil {
+		client.deleteClaims(claims)
+		return nil, false, fmt.Errorf("error creating pipeline run: %s", err)
+	}
+
+	if err := client.OwnResources(created); err != nil {
+		log.Printf("created pipeline run %s but %s", created.GetName(), err)
+	}
+
+	if options.ConcurrencyPolicy == githookv1alpha1.ConcurrencyCancelPrevious {
+		return created, false, client.cancelPreviousUnstructured(resource, created)
+	}
+
+	return created, false, nil
+}
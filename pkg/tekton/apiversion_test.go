@@ -0,0 +1,272 @@
+package tekton
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func unstructuredParams(t *testing.T, pipelineRun *unstructured.Unstructured) map[string]interface{} {
+	params, _, err := unstructured.NestedSlice(pipelineRun.Object, "spec", "params")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]interface{}{}
+	for _, param := range params {
+		fields := param.(map[string]interface{})
+		values[fields["name"].(string)] = fields["value"]
+	}
+
+	return values
+}
+
+func TestGenerateUnstructuredPipelineRun(t *testing.T) {
+	options := PipelineOptions{
+		Namespace:   "ci",
+		Prefix:      "sample",
+		APIVersion:  githookv1alpha1.PipelineV1,
+		GitURL:      "https://github.com/pongsatt/githook.git",
+		GitRevision: "refs/heads/master",
+		GitCommit:   "034ab39f12bac07af0188cc9fe7b9f18fba8731f",
+		GitBranch:   "master",
+		RunSpecJSON: `{
+			"pipelineSpec": {"tasks": [{"name": "build", "taskRef": {"name": "build"}}]},
+			"params": [{"name": "image", "value": "app:$COMMIT"}, {"name": "tags", "value": ["latest"]}],
+			"workspaces": [{"name": "source", "emptyDir": {}}],
+			"taskRunTemplate": {"serviceAccountName": "builder"}
+		}`,
+		Params: []githookv1alpha1.ParamMapping{{Name: "image", Variable: "BRANCH"}},
+	}
+
+	pipelineRun, err := generateUnstructuredPipelineRun(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pipelineRun.GetAPIVersion() != "tekton.dev/v1" || pipelineRun.GetKind() != "PipelineRun" || pipelineRun.GetGenerateName() != "sample-" {
+		t.Fatalf("unexpected pipelinerun %v", pipelineRun.Object)
+	}
+
+	if pipelineRun.GetLabels()[LabelGitHookName] != "sample" || pipelineRun.GetAnnotations()[AnnotationCommit] != options.GitCommit {
+		t.Fatalf("expected provenance labels and annotations but got %v %v", pipelineRun.GetLabels(), pipelineRun.GetAnnotations())
+	}
+
+	params := unstructuredParams(t, pipelineRun)
+
+	if params[ParamGitURL] != options.GitURL || params[ParamGitRevision] != options.GitCommit {
+		t.Fatalf("expected git params but got %v", params)
+	}
+
+	if params["image"] != "master" || len(params["tags"].([]interface{})) != 1 {
+		t.Fatalf("expected param mappings to replace runspec params but got %v", params)
+	}
+
+	for _, field := range []string{"pipelineSpec", "workspaces", "taskRunTemplate"} {
+		if _, ok := pipelineRun.Object["spec"].(map[string]interface{})[field]; !ok {
+			t.Fatalf("expected %s to be kept in spec %v", field, pipelineRun.Object["spec"])
+		}
+	}
+
+	options.RunSpecJSON = `{"pipelineRef": {"name": "build"}, "params": [{"name": "git-revision", "value": "$REVISION"}]}`
+	pipelineRun, err = generateUnstructuredPipelineRun(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if params := unstructuredParams(t, pipelineRun); params[ParamGitRevision] != "refs/heads/master" {
+		t.Fatalf("expected git param given in runspec to be kept but got %v", params)
+	}
+}
+
+func TestCreateUnstructuredPipelineRunCancelPrevious(t *testing.T) {
+	newRunning := func(name, branch string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "tekton.dev/v1beta1",
+			"kind":       "PipelineRun",
+			"metadata": map[string]interface{}{
+				"name":        name,
+				"namespace":   "ci",
+				"labels":      map[string]interface{}{LabelGitHookName: "sample", LabelBranch: "feature-a"},
+				"annotations": map[string]interface{}{AnnotationGitHookName: "sample", AnnotationBranch: branch},
+			},
+			"status": map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Succeeded", "status": "Unknown"}},
+			},
+		}}
+	}
+
+	// feature/a and feature-a share the sanitized branch label
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), newRunning("sample-running", "feature-a"), newRunning("sample-other-branch", "feature/a"))
+	// the fake does not generate names
+	dynamicClient.PrependReactor("create", "pipelineruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		created.SetName(created.GetGenerateName() + "created")
+		return true, created, nil
+	})
+	client := &Client{dynamic: dynamicClient}
+
+	options := PipelineOptions{
+		Namespace:         "ci",
+		Prefix:            "sample",
+		APIVersion:        githookv1alpha1.PipelineV1beta1,
+		GitBranch:         "feature-a",
+		RunSpecJSON:       `{"pipelineRef": {"name": "build"}}`,
+		ConcurrencyPolicy: githookv1alpha1.ConcurrencyCancelPrevious,
+	}
+
+	if _, _, err := client.createUnstructuredPipelineRun(options); err != nil {
+		t.Fatal(err)
+	}
+
+	resource, _ := pipelineRunResource(githookv1alpha1.PipelineV1beta1)
+	cancelled, err := client.dynamic.Resource(resource).Namespace("ci").Get("sample-running", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status, _, _ := unstructured.NestedString(cancelled.Object, "spec", "status"); status != "PipelineRunCancelled" {
+		t.Fatalf("expected running pipelinerun to be cancelled but got status %q", status)
+	}
+
+	otherBranch, err := client.dynamic.Resource(resource).Namespace("ci").Get("sample-other-branch", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status, _, _ := unstructured.NestedString(otherBranch.Object, "spec", "status"); status != "" {
+		t.Fatalf("expected pipelinerun of another branch not to be cancelled but got status %q", status)
+	}
+}
+
+func TestCreateUnstructuredPipelineRunQueue(t *testing.T) {
+	running := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1beta1",
+		"kind":       "PipelineRun",
+		"metadata": map[string]interface{}{
+			"name":        "sample-running",
+			"namespace":   "ci",
+			"labels":      map[string]interface{}{LabelGitHookName: "sample", LabelBranch: "master"},
+			"annotations": map[string]interface{}{AnnotationGitHookName: "sample", AnnotationBranch: "master"},
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Succeeded", "status": "Unknown"}},
+		},
+	}}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), running)
+	// the fake does not generate names
+	dynamicClient.PrependReactor("create", "pipelineruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		created.SetName(created.GetGenerateName() + "created")
+		return false, created, nil
+	})
+	client := &Client{kube: kubefake.NewSimpleClientset(), dynamic: dynamicClient}
+
+	options := PipelineOptions{
+		Namespace:         "ci",
+		Prefix:            "sample",
+		APIVersion:        githookv1alpha1.PipelineV1beta1,
+		GitBranch:         "master",
+		RunSpecJSON:       `{"pipelineRef": {"name": "build"}}`,
+		ConcurrencyPolicy: githookv1alpha1.ConcurrencyQueue,
+	}
+
+	_, queued, err := client.createUnstructuredPipelineRun(options)
+	if err != nil || !queued {
+		t.Fatalf("expected pipelinerun to be queued but got %v, %v", queued, err)
+	}
+
+	resource, _ := pipelineRunResource(githookv1alpha1.PipelineV1beta1)
+	pipelineRuns := client.dynamic.Resource(resource).Namespace("ci")
+
+	unstructured.SetNestedSlice(running.Object, []interface{}{map[string]interface{}{"type": "Succeeded", "status": "True"}}, "status", "conditions")
+	if _, err := pipelineRuns.Update(running, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	started, err := client.StartQueued(githookv1alpha1.PipelineV1beta1, running)
+	if err != nil || started == nil {
+		t.Fatalf("expected queued pipelinerun to start but got %v, %v", started, err)
+	}
+
+	created, err := pipelineRuns.Get("sample-created", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected queued pipelinerun to be created as tekton.dev/v1beta1 but got %s", err)
+	}
+
+	if ref, _, _ := unstructured.NestedString(created.Object, "spec", "pipelineRef", "name"); ref != "build" || created.GetAnnotations()[AnnotationBranch] != "master" {
+		t.Fatalf("unexpected started pipelinerun %v", created.Object)
+	}
+
+	configMaps, _ := client.kube.CoreV1().ConfigMaps("ci").List(metav1.ListOptions{})
+	if len(configMaps.Items) != 0 {
+		t.Fatalf("expected queued pipelinerun to be removed but got %d", len(configMaps.Items))
+	}
+}
+
+func TestCreateUnstructuredPipelineRunOwnClaimsFailure(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.PrependReactor("patch", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	// the fake does not generate names
+	dynamicClient.PrependReactor("create", "pipelineruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		created := action.(k8stesting.CreateAction).GetObject().(*unstructured.Unstructured)
+		created.SetName(created.GetGenerateName() + "created")
+		return true, created, nil
+	})
+	client := &Client{kube: kubeClient, dynamic: dynamicClient}
+
+	options := PipelineOptions{
+		Namespace:   "ci",
+		Prefix:      "sample",
+		APIVersion:  githookv1alpha1.PipelineV1,
+		RunSpecJSON: `{"pipelineRef": {"name": "build"}}`,
+		Workspaces: []githookv1alpha1.WorkspaceTemplate{
+			{Name: "source", PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{}},
+		},
+	}
+
+	// owners are set by the PipelineRun controller once the pipelinerun is created
+	pipelineRun, _, err := client.createUnstructuredPipelineRun(options)
+	if err != nil {
+		t.Fatalf("expected created pipelinerun despite claims without owner but got %s", err)
+	}
+
+	if pipelineRun.GetName() != "sample-created" {
+		t.Fatalf("expected created pipelinerun but got %s", pipelineRun.GetName())
+	}
+}
+
+func TestServedAPIVersions(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "tekton.dev/v1alpha1", APIResources: []metav1.APIResource{{Name: "runs"}}},
+		{GroupVersion: "tekton.dev/v1beta1", APIResources: []metav1.APIResource{{Name: "pipelineruns"}, {Name: "taskruns"}}},
+		{GroupVersion: "tekton.dev/v1", APIResources: []metav1.APIResource{{Name: "pipelineruns"}}},
+		{GroupVersion: "serving.knative.dev/v1", APIResources: []metav1.APIResource{{Name: "services"}}},
+	}
+	client := &Client{kube: kubeClient}
+
+	served, err := client.ServedAPIVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []githookv1alpha1.PipelineAPIVersion{githookv1alpha1.PipelineV1beta1, githookv1alpha1.PipelineV1}
+	if !reflect.DeepEqual(served, expected) {
+		t.Fatalf("expected served api versions %v but got %v", expected, served)
+	}
+}
@@ -3,16 +3,23 @@ package tekton
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 
 	v1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 // Client provides tekton client
 type Client struct {
-	tekton *versioned.Clientset
+	tekton  versioned.Interface
+	kube    kubernetes.Interface
+	dynamic dynamic.Interface
 }
 
 // PipelineOptions stores pipeline options
@@ -22,118 +29,193 @@ type PipelineOptions struct {
 	GitURL      string
 	GitRevision string
 	GitCommit   string
+	GitBranch   string
+	GitTag      string
 	RunSpecJSON string
+
+	RepoOwner  string
+	RepoName   string
+	EventType  string
+	DeliveryID string
+	Author     string
+	Committer  string
+	Sender     string
+
+	// ChangedFiles are files changed by the event or nil if unknown
+	ChangedFiles      []string
+	PullRequestNumber int
+	SourceBranch      string
+	TargetBranch      string
+
+	// RenderMode is the way runspec is rendered, "template" or "vars" (default)
+	RenderMode string
+	// Payload is the decoded webhook payload available to templates and jsonpath params
+	Payload interface{}
+	// Params are appended to pipelinerun params with values taken from the event
+	Params []githookv1alpha1.ParamMapping
+	// APIVersion is the tekton api version of pipelinerun created
+	APIVersion githookv1alpha1.PipelineAPIVersion
+	// Workspaces bind workspaces of tekton.dev/v1beta1 and tekton.dev/v1 pipelinerun to fresh volumes
+	Workspaces []githookv1alpha1.WorkspaceTemplate
+	// ConcurrencyPolicy is the way pipelineruns of the same branch or pull request run
+	ConcurrencyPolicy githookv1alpha1.ConcurrencyPolicy
+	// Trigger is the name of the GitHook trigger matched the event or empty without triggers
+	Trigger string
 }
 
 // New creates new tekton client instance
 func New() (*Client, error) {
-	config := ctrl.GetConfigOrDie()
+	return NewForConfig(ctrl.GetConfigOrDie())
+}
 
+// NewForConfig creates new tekton client instance for the given config
+func NewForConfig(config *rest.Config) (*Client, error) {
 	clientset, err := versioned.NewForConfig(config)
 
 	if err != nil {
 		return nil, err
 	}
 
+	kubeClientset, err := kubernetes.NewForConfig(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
-		tekton: clientset,
+		tekton:  clientset,
+		kube:    kubeClientset,
+		dynamic: dynamicClient,
 	}, nil
 }
 
-func (client *Client) getOrCreateGitPipelineResource(namespace, prefix, gitURL, revision string) (string, error) {
+// CreatePipelineRun creates new pipeline run of the api version of options applying its
+// concurrency policy. It returns true if the pipeline run is queued to be created when
+// running ones finish. Previous pipeline runs which failed to be cancelled are reported
+// as error along with the created pipeline run.
+func (client *Client) CreatePipelineRun(options PipelineOptions) (metav1.Object, bool, error) {
+	if !usesPipelineResources(options.APIVersion) {
+		pipelineRun, queued, err := client.createUnstructuredPipelineRun(options)
+		// a nil pipelinerun must not be returned as non nil metav1.Object
+		if pipelineRun == nil {
+			return nil, false, err
+		}
+		return pipelineRun, queued, err
+	}
+
+	if len(options.Workspaces) > 0 {
+		return nil, false, fmt.Errorf("workspaces are not supported by %s pipelineruns", githookv1alpha1.PipelineV1alpha1)
+	}
 
-	tektonClient := client.tekton.TektonV1alpha1()
-	// search for existing resource
-	list, err := tektonClient.PipelineResources(namespace).List(metav1.ListOptions{})
+	pipelineRun, err := client.generatePipelineRun(options)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to list pipeline resource: %s", err)
+		return nil, false, err
 	}
 
-	for _, item := range list.Items {
-		if item.Spec.Type == v1alpha1.PipelineResourceTypeGit {
-			gitResource, _ := v1alpha1.NewGitResource(&item)
-			if gitResource.URL == gitURL {
-				return item.Name, nil
-			}
-		}
+	queued, err := client.queueWhileRunning(options.ConcurrencyPolicy, options.APIVersion, pipelineRun)
+
+	if err != nil && !queued {
+		client.deleteGitPipelineResource(pipelineRun)
 	}
 
-	// create new
-	gitResource := &v1alpha1.PipelineResource{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: fmt.Sprintf("%s-git-source-", prefix),
-			Namespace:    namespace,
-		},
-		Spec: v1alpha1.PipelineResourceSpec{
-			Type: v1alpha1.PipelineResourceTypeGit,
-			Params: []v1alpha1.Param{
-				v1alpha1.Param{
-					Name:  "url",
-					Value: gitURL,
-				},
-				v1alpha1.Param{
-					Name:  "rivision",
-					Value: revision,
-				},
-			},
-		},
+	if err != nil || queued {
+		return pipelineRun, queued, err
 	}
 
-	gitResource, err = tektonClient.PipelineResources(namespace).Create(gitResource)
+	created, err := client.tekton.TektonV1alpha1().PipelineRuns(options.Namespace).Create(pipelineRun)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to create pipeline resource: %s", err)
+		client.deleteGitPipelineResource(pipelineRun)
+		return nil, false, fmt.Errorf("error creating pipeline run: %s", err)
+	}
+
+	if err := client.OwnResources(created); err != nil {
+		log.Printf("created pipeline run %s but %s", created.Name, err)
 	}
 
-	return gitResource.Name, nil
+	return created, false, client.cancelPrevious(options.ConcurrencyPolicy, created)
+}
+
+// OwnResources makes pipelinerun owner of git pipelineresource and workspace claims created for it
+// so they are deleted with the pipelinerun. Pipelinerun creation sets owners as well but only logs
+// failures since failing the event once pipelinerun is created would create it again when the event
+// is redelivered. The PipelineRun controller calls OwnResources until owners are set.
+func (client *Client) OwnResources(pipelineRun metav1.Object) error {
+	return client.ownResources(pipelineRun, pipelineRunOwner(pipelineRun))
 }
 
-// CreatePipelineRun creates new pipeline run
-func (client *Client) CreatePipelineRun(options PipelineOptions) (*v1alpha1.PipelineRun, error) {
-	return client.generatePipelineRun(options)
+// ownResources sets owner of git pipelineresource and workspace claims created for pipelinerun
+// so they are deleted with the owner
+func (client *Client) ownResources(pipelineRun metav1.Object, owner metav1.OwnerReference) error {
+	if err := client.ownGitPipelineResource(pipelineRun, owner); err != nil {
+		return err
+	}
+
+	return client.ownClaims(pipelineRun, owner)
+}
+
+// deleteResources deletes git pipelineresource and workspace claims created for pipelinerun
+// failed to be created
+func (client *Client) deleteResources(pipelineRun metav1.Object) {
+	client.deleteGitPipelineResource(pipelineRun)
+	client.deleteClaims(workspaceClaims(pipelineRun))
 }
 
 func (client *Client) generatePipelineRun(options PipelineOptions) (*v1alpha1.PipelineRun, error) {
 
+	runSpecJSON, err := renderRunSpec(options)
+
+	if err != nil {
+		return nil, err
+	}
+
 	pipelineRunSpec := &v1alpha1.PipelineRunSpec{}
-	err := json.Unmarshal([]byte(replaceVars(options.RunSpecJSON, options)), pipelineRunSpec)
+	err = json.Unmarshal([]byte(runSpecJSON), pipelineRunSpec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := buildParams(options.Params, options)
 
 	if err != nil {
 		return nil, err
 	}
+	pipelineRunSpec.Params = mergeParams(pipelineRunSpec.Params, params)
 
 	pipelineRun := &v1alpha1.PipelineRun{}
 	pipelineRun.Spec = *pipelineRunSpec
 	pipelineRun.ObjectMeta = metav1.ObjectMeta{
 		GenerateName: fmt.Sprintf("%s-", options.Prefix),
 		Namespace:    options.Namespace,
+		Labels:       pipelineRunLabels(options),
+		Annotations:  pipelineRunAnnotations(options),
 	}
 
 	if len(pipelineRun.Spec.Resources) == 0 {
-		gitResourceName, err := client.getOrCreateGitPipelineResource(options.Namespace, options.Prefix, options.GitURL, options.GitRevision)
+		gitResource, err := client.createGitPipelineResource(options)
 
 		if err != nil {
 			return nil, err
 		}
 
+		pipelineRun.Annotations[AnnotationGitResource] = gitResource.Name
 		pipelineRun.Spec.Resources = []v1alpha1.PipelineResourceBinding{
 			v1alpha1.PipelineResourceBinding{
 				Name: "git-source",
 				ResourceRef: v1alpha1.PipelineResourceRef{
-					Name: gitResourceName,
+					Name: gitResource.Name,
 				},
 			},
 		}
 	}
 
-	tektonClient := client.tekton.TektonV1alpha1()
-
-	pipelineRun, err = tektonClient.PipelineRuns(options.Namespace).Create(pipelineRun)
-
-	if err != nil {
-		return nil, fmt.Errorf("error creating pipeline run: %s", err)
-	}
-
 	return pipelineRun, nil
 }
@@ -0,0 +1,454 @@
+package tekton
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/knative/pkg/apis"
+	v1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// LabelQueued is the label of configmap holding a pipelinerun waiting for running ones to finish
+	LabelQueued = provenancePrefix + "queued"
+
+	// annotationQueuedAt is the annotation of queued configmap holding the time it was queued
+	annotationQueuedAt = provenancePrefix + "queued-at"
+
+	// queuedPipelineRunKey is the configmap key holding queued pipelinerun of any api version in json format
+	queuedPipelineRunKey = "pipelinerun.json"
+)
+
+// cancelPatch sets spec status of pipelinerun to cancelled
+var cancelPatch = []byte(fmt.Sprintf(`{"spec":{"status":%q}}`, v1alpha1.PipelineRunSpecStatusCancelled))
+
+// concurrencySelector returns the label selector of pipelineruns of the same GitHook, trigger, repository
+// and pull request or branch as the given pipelinerun labels. It is empty when the event has
+// neither pull request nor branch.
+func concurrencySelector(runLabels map[string]string) string {
+	selector := []string{LabelGitHookName + "=" + runLabels[LabelGitHookName]}
+
+	if trigger := runLabels[LabelTrigger]; trigger != "" {
+		selector = append(selector, LabelTrigger+"="+trigger)
+	}
+
+	if repo := runLabels[LabelRepo]; repo != "" {
+		selector = append(selector, LabelRepo+"="+repo)
+	}
+
+	switch {
+	case runLabels[LabelPullRequest] != "":
+		selector = append(selector, LabelPullRequest+"="+runLabels[LabelPullRequest])
+	case runLabels[LabelBranch] != "":
+		selector = append(selector, LabelBranch+"="+runLabels[LabelBranch], "!"+LabelPullRequest)
+	default:
+		return ""
+	}
+
+	return strings.Join(selector, ",")
+}
+
+// sameConcurrencyKey checks if annotations hold the same GitHook, trigger, repository and pull request
+// or branch as runAnnotations. Labels of concurrencySelector are sanitized and truncated so pipelineruns
+// of different branches, ex. feature/a and feature-a, may share them.
+func sameConcurrencyKey(annotations, runAnnotations map[string]string) bool {
+	keys := []string{AnnotationGitHookName, AnnotationTrigger, AnnotationRepoOwner, AnnotationRepoName, AnnotationPullRequest}
+	if runAnnotations[AnnotationPullRequest] == "" {
+		keys = append(keys, AnnotationBranch)
+	}
+
+	for _, key := range keys {
+		if annotations[key] != runAnnotations[key] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// runningPipelineRuns lists other pipelineruns of the same branch or pull request as pipelinerun
+// which are not done
+func (client *Client) runningPipelineRuns(pipelineRun metav1.Object) ([]v1alpha1.PipelineRun, error) {
+	selector := concurrencySelector(pipelineRun.GetLabels())
+
+	if selector == "" {
+		return nil, nil
+	}
+
+	list, err := client.tekton.TektonV1alpha1().PipelineRuns(pipelineRun.GetNamespace()).List(metav1.ListOptions{LabelSelector: selector})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline runs: %s", err)
+	}
+
+	running := []v1alpha1.PipelineRun{}
+	for _, item := range list.Items {
+		if item.Name != pipelineRun.GetName() && !item.IsDone() && sameConcurrencyKey(item.Annotations, pipelineRun.GetAnnotations()) {
+			running = append(running, item)
+		}
+	}
+
+	return running, nil
+}
+
+// isUnstructuredDone checks if unstructured pipelinerun has finished
+func isUnstructuredDone(pipelineRun *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(pipelineRun.Object, "status", "conditions")
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]interface{})
+		if ok && fields["type"] == string(apis.ConditionSucceeded) {
+			return fields["status"] != string(corev1.ConditionUnknown)
+		}
+	}
+
+	return false
+}
+
+// runningUnstructuredPipelineRuns lists other tekton.dev/v1beta1 or tekton.dev/v1 pipelineruns of the
+// same branch or pull request as pipelinerun which are not done
+func (client *Client) runningUnstructuredPipelineRuns(resource schema.GroupVersionResource, pipelineRun metav1.Object) ([]unstructured.Unstructured, error) {
+	selector := concurrencySelector(pipelineRun.GetLabels())
+
+	if selector == "" {
+		return nil, nil
+	}
+
+	list, err := client.dynamic.Resource(resource).Namespace(pipelineRun.GetNamespace()).List(metav1.ListOptions{LabelSelector: selector})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline runs: %s", err)
+	}
+
+	running := []unstructured.Unstructured{}
+	for _, item := range list.Items {
+		if item.GetName() != pipelineRun.GetName() && !isUnstructuredDone(&item) && sameConcurrencyKey(item.GetAnnotations(), pipelineRun.GetAnnotations()) {
+			running = append(running, item)
+		}
+	}
+
+	return running, nil
+}
+
+// countRunning counts other pipelineruns of api version of the same branch or pull request
+// as pipelinerun which are not done
+func (client *Client) countRunning(apiVersion githookv1alpha1.PipelineAPIVersion, pipelineRun metav1.Object) (int, error) {
+	if usesPipelineResources(apiVersion) {
+		running, err := client.runningPipelineRuns(pipelineRun)
+		return len(running), err
+	}
+
+	resource, err := pipelineRunResource(apiVersion)
+
+	if err != nil {
+		return 0, err
+	}
+
+	running, err := client.runningUnstructuredPipelineRuns(resource, pipelineRun)
+	return len(running), err
+}
+
+// cancelPipelineRuns cancels pipelineruns which are not cancelled yet
+func (client *Client) cancelPipelineRuns(pipelineRuns []v1alpha1.PipelineRun) error {
+	for _, pipelineRun := range pipelineRuns {
+		if pipelineRun.IsCancelled() {
+			continue
+		}
+
+		_, err := client.tekton.TektonV1alpha1().PipelineRuns(pipelineRun.Namespace).Patch(pipelineRun.Name, types.MergePatchType, cancelPatch)
+
+		if errors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to cancel pipeline run %s: %s", pipelineRun.Name, err)
+		}
+
+		log.Printf("cancelled pipeline run %s superseded by new event", pipelineRun.Name)
+	}
+
+	return nil
+}
+
+// queuePipelineRun stores pipelinerun in a configmap to be created when running ones finish
+func (client *Client) queuePipelineRun(pipelineRun metav1.Object) (*corev1.ConfigMap, error) {
+	data, err := json.Marshal(pipelineRun)
+
+	if err != nil {
+		return nil, err
+	}
+
+	configMapLabels := map[string]string{LabelQueued: "true"}
+	for key, value := range pipelineRun.GetLabels() {
+		configMapLabels[key] = value
+	}
+
+	configMapAnnotations := map[string]string{annotationQueuedAt: time.Now().UTC().Format(time.RFC3339Nano)}
+	for key, value := range pipelineRun.GetAnnotations() {
+		configMapAnnotations[key] = value
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pipelineRun.GetGenerateName() + "queued-",
+			Namespace:    pipelineRun.GetNamespace(),
+			Labels:       configMapLabels,
+			Annotations:  configMapAnnotations,
+		},
+		Data: map[string]string{
+			queuedPipelineRunKey: string(data),
+		},
+	}
+
+	configMap, err = client.kube.CoreV1().ConfigMaps(pipelineRun.GetNamespace()).Create(configMap)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue pipeline run: %s", err)
+	}
+
+	return configMap, nil
+}
+
+// decodeQueued decodes pipelinerun queued in json format as tekton.dev/v1alpha1 pipelinerun,
+// or as unstructured pipelinerun of its api version
+func decodeQueued(data string) (metav1.Object, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal([]byte(data), &typeMeta); err != nil {
+		return nil, err
+	}
+
+	if usesPipelineResources(githookv1alpha1.PipelineAPIVersion(typeMeta.APIVersion)) {
+		pipelineRun := &v1alpha1.PipelineRun{}
+		return pipelineRun, json.Unmarshal([]byte(data), pipelineRun)
+	}
+
+	object := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(data), &object); err != nil {
+		return nil, err
+	}
+
+	return &unstructured.Unstructured{Object: object}, nil
+}
+
+// createQueued creates pipelinerun decoded from the queue
+func (client *Client) createQueued(pipelineRun metav1.Object) (metav1.Object, error) {
+	object, ok := pipelineRun.(*unstructured.Unstructured)
+
+	if !ok {
+		return client.tekton.TektonV1alpha1().PipelineRuns(pipelineRun.GetNamespace()).Create(pipelineRun.(*v1alpha1.PipelineRun))
+	}
+
+	resource, err := pipelineRunResource(githookv1alpha1.PipelineAPIVersion(object.GetAPIVersion()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.dynamic.Resource(resource).Namespace(object.GetNamespace()).Create(object, metav1.CreateOptions{})
+}
+
+// StartQueued creates the oldest pipelinerun queued for the GitHook and branch or pull request
+// of the finished pipelinerun of api version when none of their pipelineruns is running.
+// It returns nil when there is nothing to start.
+func (client *Client) StartQueued(apiVersion githookv1alpha1.PipelineAPIVersion, finished metav1.Object) (metav1.Object, error) {
+	selector := concurrencySelector(finished.GetLabels())
+
+	if selector == "" {
+		return nil, nil
+	}
+
+	running, err := client.countRunning(apiVersion, finished)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if running > 0 {
+		return nil, nil
+	}
+
+	namespace := finished.GetNamespace()
+	configMaps := client.kube.CoreV1().ConfigMaps(namespace)
+	list, err := configMaps.List(metav1.ListOptions{LabelSelector: selector + "," + LabelQueued + "=true"})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queued pipeline runs: %s", err)
+	}
+
+	queued := []corev1.ConfigMap{}
+	for _, item := range list.Items {
+		if sameConcurrencyKey(item.Annotations, finished.GetAnnotations()) {
+			queued = append(queued, item)
+		}
+	}
+
+	if len(queued) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(queued, func(i, j int) bool {
+		return queued[i].Annotations[annotationQueuedAt] < queued[j].Annotations[annotationQueuedAt]
+	})
+	oldest := queued[0]
+
+	pipelineRun, err := decodeQueued(oldest.Data[queuedPipelineRunKey])
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid queued pipeline run %s: %s", oldest.Name, err)
+	}
+
+	// deleting the configmap claims the queued pipelinerun so it is created once.
+	// Its git pipelineresource and workspace claims are orphaned to be owned by the pipelinerun.
+	orphan := metav1.DeletePropagationOrphan
+	err = configMaps.Delete(oldest.Name, &metav1.DeleteOptions{
+		Preconditions:     metav1.NewUIDPreconditions(string(oldest.UID)),
+		PropagationPolicy: &orphan,
+	})
+
+	if errors.IsNotFound(err) || errors.IsConflict(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue pipeline run %s: %s", oldest.Name, err)
+	}
+
+	created, err := client.createQueued(pipelineRun)
+
+	if err != nil {
+		client.deleteResources(pipelineRun)
+		return nil, fmt.Errorf("error creating queued pipeline run: %s", err)
+	}
+
+	if err := client.OwnResources(created); err != nil {
+		log.Printf("created queued pipeline run %s but %s", created.GetName(), err)
+	}
+
+	return created, nil
+}
+
+// cancelPrevious cancels running pipelineruns of the same branch or pull request as the created
+// pipelinerun when concurrency policy is CancelPrevious. They are cancelled once the new one is
+// created so they are not cancelled without being replaced.
+func (client *Client) cancelPrevious(policy githookv1alpha1.ConcurrencyPolicy, created *v1alpha1.PipelineRun) error {
+	if policy != githookv1alpha1.ConcurrencyCancelPrevious {
+		return nil
+	}
+
+	running, err := client.runningPipelineRuns(created)
+
+	if err != nil {
+		return err
+	}
+
+	return client.cancelPipelineRuns(running)
+}
+
+// queueWhileRunning queues the pipelinerun of api version when concurrency policy is Queue and
+// pipelineruns of the same branch or pull request are running. It returns true if pipelinerun is queued.
+func (client *Client) queueWhileRunning(policy githookv1alpha1.ConcurrencyPolicy, apiVersion githookv1alpha1.PipelineAPIVersion, pipelineRun metav1.Object) (bool, error) {
+	if policy != githookv1alpha1.ConcurrencyQueue {
+		return false, nil
+	}
+
+	running, err := client.countRunning(apiVersion, pipelineRun)
+
+	if err != nil {
+		return false, err
+	}
+
+	if running == 0 {
+		return false, nil
+	}
+
+	configMap, err := client.queuePipelineRun(pipelineRun)
+
+	if err != nil {
+		return false, err
+	}
+
+	log.Printf("pipeline run queued as %s until %d running pipeline runs finish", configMap.Name, running)
+
+	// git pipelineresource and workspace claims are deleted with the queued pipelinerun until it is created
+	err = client.ownResources(pipelineRun, metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       configMap.Name,
+		UID:        configMap.UID,
+	})
+
+	if err != nil {
+		return client.unqueue(configMap, fmt.Errorf("failed to queue pipeline run: %s", err))
+	}
+
+	// running pipelineruns may finish before the pipelinerun is queued
+	if _, err := client.StartQueued(apiVersion, pipelineRun); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// unqueue deletes the queued configmap of pipelinerun failed to be queued. It returns true when the
+// pipelinerun is started meanwhile, or cannot be unqueued, and stays owner of its resources then.
+func (client *Client) unqueue(configMap *corev1.ConfigMap, cause error) (bool, error) {
+	err := client.kube.CoreV1().ConfigMaps(configMap.Namespace).Delete(configMap.Name, &metav1.DeleteOptions{
+		Preconditions: metav1.NewUIDPreconditions(string(configMap.UID)),
+	})
+
+	if errors.IsNotFound(err) || errors.IsConflict(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		log.Printf("%s and failed to unqueue it: %s", cause, err)
+		return true, nil
+	}
+
+	return false, cause
+}
+
+// cancelPreviousUnstructured cancels running tekton.dev/v1beta1 or tekton.dev/v1 pipelineruns
+// of the same branch or pull request as the created pipelinerun
+func (client *Client) cancelPreviousUnstructured(resource schema.GroupVersionResource, created *unstructured.Unstructured) error {
+	running, err := client.runningUnstructuredPipelineRuns(resource, created)
+
+	if err != nil {
+		return err
+	}
+
+	pipelineRuns := client.dynamic.Resource(resource).Namespace(created.GetNamespace())
+	patch := []byte(fmt.Sprintf(`{"spec":{"status":%q}}`, cancelledSpecStatus(created.GetAPIVersion())))
+
+	for _, item := range running {
+		// pipelinerun is being cancelled already
+		if status, _, _ := unstructured.NestedString(item.Object, "spec", "status"); status != "" {
+			continue
+		}
+
+		_, err := pipelineRuns.Patch(item.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+
+		if errors.IsNotFound(err) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to cancel pipeline run %s: %s", item.GetName(), err)
+		}
+
+		log.Printf("cancelled pipeline run %s superseded by new event", item.GetName())
+	}
+
+	return nil
+}
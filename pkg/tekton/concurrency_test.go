@@ -0,0 +1,291 @@
+package tekton
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/knative/pkg/apis"
+	v1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+// newPipelineRun returns pipelinerun with labels and the annotations holding their original values
+func newPipelineRun(name string, runLabels map[string]string, done bool) *v1alpha1.PipelineRun {
+	annotations := map[string]string{}
+	for key, value := range runLabels {
+		annotations[key] = value
+	}
+
+	pipelineRun := &v1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Labels:      runLabels,
+			Annotations: annotations,
+		},
+	}
+
+	if done {
+		pipelineRun.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue})
+	}
+
+	return pipelineRun
+}
+
+// fakePipelineRuns serves pipelineruns api of the default namespace since the generated
+// tekton fake clientset does not build with the client-go version in use.
+// Patches of pipelineresources are recorded by name, or fail when failResourcePatches is set.
+type fakePipelineRuns struct {
+	sync.Mutex
+	items               map[string]*v1alpha1.PipelineRun
+	resourcePatches     map[string][]string
+	failResourcePatches bool
+}
+
+func (f *fakePipelineRuns) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	const path = "/apis/tekton.dev/v1alpha1/namespaces/default/pipelineruns"
+	const resourcesPath = "/apis/tekton.dev/v1alpha1/namespaces/default/pipelineresources/"
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, path), "/")
+	w.Header().Set("Content-Type", "application/json")
+
+	if strings.HasPrefix(r.URL.Path, resourcesPath) && r.Method == http.MethodPatch {
+		if f.failResourcePatches {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		name := strings.TrimPrefix(r.URL.Path, resourcesPath)
+		f.resourcePatches[name] = append(f.resourcePatches[name], string(body))
+		json.NewEncoder(w).Encode(&v1alpha1.PipelineResource{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && name == "":
+		selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		list := &v1alpha1.PipelineRunList{}
+		for _, item := range f.items {
+			if selector.Matches(labels.Set(item.Labels)) {
+				list.Items = append(list.Items, *item)
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+	case r.Method == http.MethodPost:
+		item := &v1alpha1.PipelineRun{}
+		json.NewDecoder(r.Body).Decode(item)
+		item.Name = item.GenerateName + "created"
+		f.items[item.Name] = item
+		json.NewEncoder(w).Encode(item)
+	case r.Method == http.MethodPatch && f.items[name] != nil:
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != string(cancelPatch) {
+			http.Error(w, "unexpected patch "+string(body), http.StatusBadRequest)
+			return
+		}
+		f.items[name].Spec.Status = v1alpha1.PipelineRunSpecStatusCancelled
+		json.NewEncoder(w).Encode(f.items[name])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeClient(t *testing.T, pipelineRuns ...*v1alpha1.PipelineRun) (*Client, *fakePipelineRuns, *httptest.Server) {
+	fake := &fakePipelineRuns{items: map[string]*v1alpha1.PipelineRun{}, resourcePatches: map[string][]string{}}
+	for _, pipelineRun := range pipelineRuns {
+		fake.items[pipelineRun.Name] = pipelineRun
+	}
+
+	server := httptest.NewServer(fake)
+
+	clientset, err := versioned.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Client{
+		tekton: clientset,
+		kube:   kubefake.NewSimpleClientset(),
+	}, fake, server
+}
+
+func TestConcurrencySelector(t *testing.T) {
+	testcases := []struct {
+		runLabels        map[string]string
+		expectedSelector string
+	}{
+		{
+			runLabels:        map[string]string{LabelGitHookName: "sample", LabelBranch: "master", LabelCommit: "abc"},
+			expectedSelector: LabelGitHookName + "=sample," + LabelBranch + "=master,!" + LabelPullRequest,
+		},
+		{
+			runLabels:        map[string]string{LabelGitHookName: "sample", LabelRepo: "githook", LabelBranch: "feature", LabelPullRequest: "12"},
+			expectedSelector: LabelGitHookName + "=sample," + LabelRepo + "=githook," + LabelPullRequest + "=12",
+		},
+		{
+			runLabels:        map[string]string{LabelGitHookName: "sample", LabelTrigger: "deploy", LabelBranch: "main"},
+			expectedSelector: LabelGitHookName + "=sample," + LabelTrigger + "=deploy," + LabelBranch + "=main,!" + LabelPullRequest,
+		},
+		{
+			runLabels:        map[string]string{LabelGitHookName: "sample", LabelCommit: "abc"},
+			expectedSelector: "",
+		},
+	}
+
+	for _, testcase := range testcases {
+		if selector := concurrencySelector(testcase.runLabels); selector != testcase.expectedSelector {
+			t.Fatalf("expected selector %q but got %q", testcase.expectedSelector, selector)
+		}
+	}
+}
+
+func TestCancelPrevious(t *testing.T) {
+	branchLabels := map[string]string{LabelGitHookName: "sample", LabelBranch: "feature-a"}
+
+	// feature/a and feature-a share the sanitized branch label
+	otherBranch := newPipelineRun("other-branch-same-label", branchLabels, false)
+	otherBranch.Annotations[AnnotationBranch] = "feature/a"
+
+	created := newPipelineRun("created", branchLabels, false)
+
+	client, fake, server := newFakeClient(t,
+		newPipelineRun("running", branchLabels, false),
+		newPipelineRun("finished", branchLabels, true),
+		newPipelineRun("other-branch", map[string]string{LabelGitHookName: "sample", LabelBranch: "develop"}, false),
+		otherBranch,
+		created,
+	)
+	defer server.Close()
+
+	if err := client.cancelPrevious(githookv1alpha1.ConcurrencyCancelPrevious, created); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for name, expectedCancelled := range map[string]bool{"running": true, "finished": false, "other-branch": false, "other-branch-same-label": false, "created": false} {
+		if fake.items[name].IsCancelled() != expectedCancelled {
+			t.Fatalf("expected pipeline run %s cancelled to be %v", name, expectedCancelled)
+		}
+	}
+}
+
+func TestQueueWhileRunning(t *testing.T) {
+	prLabels := map[string]string{LabelGitHookName: "sample", LabelBranch: "feature", LabelPullRequest: "12"}
+	finished := &metav1.ObjectMeta{Namespace: "default", Labels: prLabels, Annotations: newPipelineRun("", prLabels, false).Annotations}
+
+	client, fake, server := newFakeClient(t, newPipelineRun("running", prLabels, false))
+	defer server.Close()
+
+	pipelineRun := newPipelineRun("", prLabels, false)
+	pipelineRun.GenerateName = "sample-"
+	pipelineRun.Annotations[AnnotationGitResource] = "sample-git-source-x7k2p"
+
+	queued, err := client.queueWhileRunning(githookv1alpha1.ConcurrencyQueue, githookv1alpha1.PipelineV1alpha1, pipelineRun)
+
+	if err != nil || !queued {
+		t.Fatalf("expected pipeline run to be queued but got %v, %v", queued, err)
+	}
+
+	started, err := client.StartQueued(githookv1alpha1.PipelineV1alpha1, finished)
+
+	if err != nil || started != nil {
+		t.Fatalf("expected queued pipeline run not to start while running but got %v, %v", started, err)
+	}
+
+	fake.items["running"].Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse})
+
+	// pull request 12 and 12 of another repository owner share labels
+	otherOwner := finished.DeepCopy()
+	otherOwner.Annotations[AnnotationRepoOwner] = "fork"
+	started, err = client.StartQueued(githookv1alpha1.PipelineV1alpha1, otherOwner)
+
+	if err != nil || started != nil {
+		t.Fatalf("expected queued pipeline run not to start by pipeline runs of another repository but got %v, %v", started, err)
+	}
+
+	started, err = client.StartQueued(githookv1alpha1.PipelineV1alpha1, finished)
+
+	if err != nil || started == nil {
+		t.Fatalf("expected queued pipeline run to start but got %v, %v", started, err)
+	}
+
+	if _, ok := started.(*v1alpha1.PipelineRun); !ok || started.GetGenerateName() != "sample-" || started.GetLabels()[LabelPullRequest] != "12" {
+		t.Fatalf("unexpected started pipeline run %v", started)
+	}
+
+	patches := fake.resourcePatches["sample-git-source-x7k2p"]
+
+	if len(patches) != 2 || !strings.Contains(patches[0], `"kind":"ConfigMap"`) || !strings.Contains(patches[1], `"name":"sample-created"`) {
+		t.Fatalf("expected git resource to be owned by queued configmap then pipelinerun but got %v", patches)
+	}
+
+	configMaps, _ := client.kube.CoreV1().ConfigMaps("default").List(metav1.ListOptions{})
+
+	if len(configMaps.Items) != 0 {
+		t.Fatalf("expected queued pipeline run to be removed but got %d", len(configMaps.Items))
+	}
+}
+
+func TestQueueWhileRunningOwnerFailure(t *testing.T) {
+	prLabels := map[string]string{LabelGitHookName: "sample", LabelBranch: "feature", LabelPullRequest: "12"}
+
+	client, fake, server := newFakeClient(t, newPipelineRun("running", prLabels, false))
+	defer server.Close()
+	fake.failResourcePatches = true
+
+	pipelineRun := newPipelineRun("", prLabels, false)
+	pipelineRun.GenerateName = "sample-"
+	pipelineRun.Annotations[AnnotationGitResource] = "sample-git-source-x7k2p"
+
+	queued, err := client.queueWhileRunning(githookv1alpha1.ConcurrencyQueue, githookv1alpha1.PipelineV1alpha1, pipelineRun)
+
+	if err == nil || queued {
+		t.Fatalf("expected pipeline run failed to be queued but got %v, %v", queued, err)
+	}
+
+	configMaps, _ := client.kube.CoreV1().ConfigMaps("default").List(metav1.ListOptions{})
+
+	if len(configMaps.Items) != 0 {
+		t.Fatalf("expected pipeline run to be unqueued but got %d queued", len(configMaps.Items))
+	}
+}
+
+func TestConcurrencyPolicyAllow(t *testing.T) {
+	branchLabels := map[string]string{LabelGitHookName: "sample", LabelBranch: "master"}
+
+	client, fake, server := newFakeClient(t, newPipelineRun("running", branchLabels, false))
+	defer server.Close()
+
+	pipelineRun := newPipelineRun("created", branchLabels, false)
+	queued, err := client.queueWhileRunning(githookv1alpha1.ConcurrencyAllow, githookv1alpha1.PipelineV1alpha1, pipelineRun)
+
+	if err != nil || queued {
+		t.Fatalf("expected pipeline run not to be queued but got %v, %v", queued, err)
+	}
+
+	if err := client.cancelPrevious(githookv1alpha1.ConcurrencyAllow, pipelineRun); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if fake.items["running"].IsCancelled() {
+		t.Fatal("expected running pipeline run not to be cancelled")
+	}
+}
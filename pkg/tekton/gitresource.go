@@ -0,0 +1,120 @@
+package tekton
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	v1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AnnotationGitResource is the annotation of pipelinerun holding name of the git
+// pipelineresource created for it
+const AnnotationGitResource = provenancePrefix + "git-resource"
+
+// GitResourceGenerateName returns the name prefix of git pipelineresources created for GitHook
+func GitResourceGenerateName(prefix string) string {
+	return fmt.Sprintf("%s-git-source-", prefix)
+}
+
+// generateGitPipelineResource returns git pipelineresource of the event pinned to its
+// commit, or its revision when the event has no commit
+func generateGitPipelineResource(options PipelineOptions) *v1alpha1.PipelineResource {
+	revision := options.GitCommit
+	if revision == "" {
+		revision = options.GitRevision
+	}
+
+	return &v1alpha1.PipelineResource{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: GitResourceGenerateName(options.Prefix),
+			Namespace:    options.Namespace,
+			Labels:       pipelineRunLabels(options),
+			Annotations:  pipelineRunAnnotations(options),
+		},
+		Spec: v1alpha1.PipelineResourceSpec{
+			Type: v1alpha1.PipelineResourceTypeGit,
+			Params: []v1alpha1.Param{
+				v1alpha1.Param{
+					Name:  "url",
+					Value: options.GitURL,
+				},
+				v1alpha1.Param{
+					Name:  "revision",
+					Value: revision,
+				},
+			},
+		},
+	}
+}
+
+// createGitPipelineResource creates git pipelineresource for a single pipelinerun
+func (client *Client) createGitPipelineResource(options PipelineOptions) (*v1alpha1.PipelineResource, error) {
+	gitResource, err := client.tekton.TektonV1alpha1().PipelineResources(options.Namespace).Create(generateGitPipelineResource(options))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipeline resource: %s", err)
+	}
+
+	return gitResource, nil
+}
+
+// pipelineRunOwner returns owner reference to tekton.dev/v1alpha1 pipelinerun,
+// or to unstructured pipelinerun of its own api version
+func pipelineRunOwner(pipelineRun metav1.Object) metav1.OwnerReference {
+	apiVersion := v1alpha1.SchemeGroupVersion.String()
+	if object, ok := pipelineRun.(*unstructured.Unstructured); ok {
+		apiVersion = object.GetAPIVersion()
+	}
+
+	return metav1.OwnerReference{
+		APIVersion: apiVersion,
+		Kind:       "PipelineRun",
+		Name:       pipelineRun.GetName(),
+		UID:        pipelineRun.GetUID(),
+	}
+}
+
+// ownGitPipelineResource sets owner of git pipelineresource created for pipelinerun
+// so it is deleted with the owner
+func (client *Client) ownGitPipelineResource(pipelineRun metav1.Object, owner metav1.OwnerReference) error {
+	name := pipelineRun.GetAnnotations()[AnnotationGitResource]
+
+	if name == "" {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"ownerReferences": []metav1.OwnerReference{owner},
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = client.tekton.TektonV1alpha1().PipelineResources(pipelineRun.GetNamespace()).Patch(name, types.MergePatchType, patch)
+
+	if err != nil {
+		return fmt.Errorf("failed to set owner of pipeline resource %s: %s", name, err)
+	}
+
+	return nil
+}
+
+// deleteGitPipelineResource deletes git pipelineresource created for pipelinerun failed to be created
+func (client *Client) deleteGitPipelineResource(pipelineRun metav1.Object) {
+	name := pipelineRun.GetAnnotations()[AnnotationGitResource]
+
+	if name == "" {
+		return
+	}
+
+	if err := client.tekton.TektonV1alpha1().PipelineResources(pipelineRun.GetNamespace()).Delete(name, &metav1.DeleteOptions{}); err != nil {
+		log.Printf("failed to delete pipeline resource %s: %s", name, err)
+	}
+}
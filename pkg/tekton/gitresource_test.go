@@ -0,0 +1,38 @@
+package tekton
+
+import (
+	"testing"
+)
+
+func TestGenerateGitPipelineResource(t *testing.T) {
+	options := PipelineOptions{
+		Namespace:   "ci",
+		Prefix:      "sample",
+		GitURL:      "https://github.com/pongsatt/githook.git",
+		GitRevision: "refs/heads/feature/login",
+		GitCommit:   "034ab39f12bac07af0188cc9fe7b9f18fba8731f",
+		GitBranch:   "feature/login",
+	}
+
+	gitResource := generateGitPipelineResource(options)
+
+	params := map[string]string{}
+	for _, param := range gitResource.Spec.Params {
+		params[param.Name] = param.Value
+	}
+
+	if params["url"] != options.GitURL || params["revision"] != options.GitCommit {
+		t.Fatalf("expected git resource pinned to commit but got %v", params)
+	}
+
+	if gitResource.GenerateName != "sample-git-source-" || gitResource.Labels[LabelBranch] != "feature-login" || gitResource.Annotations[AnnotationGitHookName] != "sample" {
+		t.Fatalf("unexpected git resource metadata %v", gitResource.ObjectMeta)
+	}
+
+	options.GitCommit = ""
+	gitResource = generateGitPipelineResource(options)
+
+	if revision := gitResource.Spec.Params[1]; revision.Name != "revision" || revision.Value != options.GitRevision {
+		t.Fatalf("expected git resource of event without commit to use revision but got %v", revision)
+	}
+}
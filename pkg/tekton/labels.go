@@ -0,0 +1,87 @@
+package tekton
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxLabelValue is the maximum length of kubernetes label value
+const maxLabelValue = 63
+
+// Labels of pipelinerun linking it to the GitHook and the event created it.
+// Values are sanitized to be valid label values.
+const (
+	// LabelGitHookName is the label of pipelinerun holding name of the GitHook created it
+	LabelGitHookName = provenancePrefix + "name"
+
+	// LabelEventType is the label of pipelinerun holding type of the event
+	LabelEventType = provenancePrefix + "event"
+
+	// LabelRepo is the label of pipelinerun holding name of the repository of the event
+	LabelRepo = provenancePrefix + "repo"
+
+	// LabelBranch is the label of pipelinerun holding branch of the event
+	LabelBranch = provenancePrefix + "branch"
+
+	// LabelCommit is the label of pipelinerun holding commit sha of the event
+	LabelCommit = provenancePrefix + "commit"
+
+	// LabelPullRequest is the label of pipelinerun holding pull request number of the event
+	LabelPullRequest = provenancePrefix + "pull-request"
+
+	// LabelDeliveryID is the label of pipelinerun holding delivery id of the event
+	LabelDeliveryID = provenancePrefix + "delivery"
+
+	// LabelSender is the label of pipelinerun holding user triggered the event
+	LabelSender = provenancePrefix + "sender"
+
+	// LabelTrigger is the label of pipelinerun holding name of the GitHook trigger matched the event
+	LabelTrigger = provenancePrefix + "trigger"
+)
+
+var invalidLabelChars = regexp.MustCompile(`[^-_.A-Za-z0-9]+`)
+
+// SanitizeLabelValue converts value to a valid label value. Invalid characters are
+// replaced with '-' and the value is truncated to 63 characters beginning and ending
+// with an alphanumeric character.
+func SanitizeLabelValue(value string) string {
+	value = invalidLabelChars.ReplaceAllString(value, "-")
+
+	if len(value) > maxLabelValue {
+		value = value[:maxLabelValue]
+	}
+
+	return strings.Trim(value, "-_.")
+}
+
+func pullRequestValue(number int) string {
+	if number <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(number)
+}
+
+// pipelineRunLabels returns labels linking pipelinerun to the GitHook and event created it
+func pipelineRunLabels(options PipelineOptions) map[string]string {
+	labels := map[string]string{}
+
+	for key, value := range map[string]string{
+		LabelGitHookName: options.Prefix,
+		LabelEventType:   options.EventType,
+		LabelRepo:        options.RepoName,
+		LabelBranch:      options.GitBranch,
+		LabelCommit:      options.GitCommit,
+		LabelPullRequest: pullRequestValue(options.PullRequestNumber),
+		LabelDeliveryID:  options.DeliveryID,
+		LabelSender:      options.Sender,
+		LabelTrigger:     options.Trigger,
+	} {
+		if value = SanitizeLabelValue(value); value != "" {
+			labels[key] = value
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,76 @@
+package tekton
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestSanitizeLabelValue(t *testing.T) {
+	testcases := []struct {
+		value          string
+		expectedOutput string
+	}{
+		{value: "master", expectedOutput: "master"},
+		{value: "feature/login", expectedOutput: "feature-login"},
+		{value: "Merge Request Hook", expectedOutput: "Merge-Request-Hook"},
+		{value: "git.pullrequest.created", expectedOutput: "git.pullrequest.created"},
+		{value: "/refs/tags/v1.0/", expectedOutput: "refs-tags-v1.0"},
+		{value: "john.doe@example.com", expectedOutput: "john.doe-example.com"},
+		{value: strings.Repeat("a", 62) + "/b", expectedOutput: strings.Repeat("a", 62)},
+		{value: "///", expectedOutput: ""},
+	}
+
+	for _, testcase := range testcases {
+		output := SanitizeLabelValue(testcase.value)
+
+		if output != testcase.expectedOutput {
+			t.Fatalf("expected %q to be sanitized to %q but got %q", testcase.value, testcase.expectedOutput, output)
+		}
+
+		if errs := validation.IsValidLabelValue(output); len(errs) > 0 {
+			t.Fatalf("expected valid label value but got %q: %v", output, errs)
+		}
+	}
+}
+
+func TestPipelineRunLabels(t *testing.T) {
+	options := PipelineOptions{
+		Prefix:            "githook-sample",
+		EventType:         "pull_request",
+		RepoOwner:         "pongsatt",
+		RepoName:          "githook",
+		GitBranch:         "feature/login",
+		GitCommit:         "034ab39f12bac07af0188cc9fe7b9f18fba8731f",
+		PullRequestNumber: 12,
+		DeliveryID:        "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		Committer:         "Pongsatt",
+		Sender:            "octocat",
+	}
+
+	labels := pipelineRunLabels(options)
+	annotations := pipelineRunAnnotations(options)
+
+	if labels[LabelBranch] != "feature-login" || annotations[AnnotationBranch] != "feature/login" {
+		t.Fatalf("expected sanitized branch label and original annotation but got %q and %q", labels[LabelBranch], annotations[AnnotationBranch])
+	}
+
+	if labels[LabelPullRequest] != "12" || labels[LabelGitHookName] != "githook-sample" || labels[LabelRepo] != "githook" {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+
+	if annotations[AnnotationRepoOwner] != "pongsatt" || annotations[AnnotationDeliveryID] != options.DeliveryID {
+		t.Fatalf("unexpected annotations %v", annotations)
+	}
+
+	if labels[LabelSender] != "octocat" || annotations[AnnotationSender] != "octocat" {
+		t.Fatalf("expected sender who triggered the event but got %q and %q", labels[LabelSender], annotations[AnnotationSender])
+	}
+
+	labels = pipelineRunLabels(PipelineOptions{Prefix: "githook-sample", EventType: "push"})
+
+	if _, ok := labels[LabelPullRequest]; ok || len(labels) != 2 {
+		t.Fatalf("expected labels of empty values to be omitted but got %v", labels)
+	}
+}
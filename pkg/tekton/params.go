@@ -0,0 +1,83 @@
+package tekton
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	v1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// evalJSONPath evaluates jsonpath expression against payload.
+// Expression may be given with or without surrounding braces.
+func evalJSONPath(expr string, payload interface{}) (string, error) {
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	parser := jsonpath.New("param").AllowMissingKeys(true)
+	if err := parser.Parse(expr); err != nil {
+		return "", fmt.Errorf("invalid jsonpath %q: %s", expr, err)
+	}
+
+	var buf bytes.Buffer
+	if err := parser.Execute(&buf, payload); err != nil {
+		return "", fmt.Errorf("failed to evaluate jsonpath %q: %s", expr, err)
+	}
+
+	return buf.String(), nil
+}
+
+// buildParams resolves values of param mappings from event data
+func buildParams(mappings []githookv1alpha1.ParamMapping, options PipelineOptions) ([]v1alpha1.Param, error) {
+	vars := Vars(options)
+	params := make([]v1alpha1.Param, 0, len(mappings))
+
+	for _, mapping := range mappings {
+		var value string
+
+		switch {
+		case mapping.Variable != "":
+			v, ok := vars[mapping.Variable]
+			if !ok {
+				return nil, fmt.Errorf("param %s: unknown variable %s", mapping.Name, mapping.Variable)
+			}
+			value = v
+		case mapping.JSONPath != "":
+			v, err := evalJSONPath(mapping.JSONPath, options.Payload)
+			if err != nil {
+				return nil, fmt.Errorf("param %s: %s", mapping.Name, err)
+			}
+			value = v
+		default:
+			return nil, fmt.Errorf("param %s: either variable or jsonPath is required", mapping.Name)
+		}
+
+		params = append(params, v1alpha1.Param{
+			Name:  mapping.Name,
+			Value: value,
+		})
+	}
+
+	return params, nil
+}
+
+// mergeParams appends params replacing existing params with the same name
+func mergeParams(existing []v1alpha1.Param, params []v1alpha1.Param) []v1alpha1.Param {
+	merged := make([]v1alpha1.Param, 0, len(existing)+len(params))
+	replaced := make(map[string]bool)
+
+	for _, param := range params {
+		replaced[param.Name] = true
+	}
+
+	for _, param := range existing {
+		if !replaced[param.Name] {
+			merged = append(merged, param)
+		}
+	}
+
+	return append(merged, params...)
+}
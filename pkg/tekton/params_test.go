@@ -0,0 +1,100 @@
+package tekton
+
+import (
+	"encoding/json"
+	"testing"
+
+	v1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+)
+
+func TestBuildParams(t *testing.T) {
+	var payload interface{}
+	err := json.Unmarshal([]byte(`{"repository":{"full_name":"pongsatt/githook","id":12},"head_commit":{"message":"fix \"quote\" \\ slash"}}`), &payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := PipelineOptions{
+		GitBranch: "master",
+		GitCommit: "034ab39f12bac07af0188cc9fe7b9f18fba8731f",
+		Payload:   payload,
+	}
+
+	mappings := []githookv1alpha1.ParamMapping{
+		{Name: "branch", Variable: "BRANCH"},
+		{Name: "sha", Variable: "COMMIT_SHA"},
+		{Name: "repo", JSONPath: "{.repository.full_name}"},
+		{Name: "repo-id", JSONPath: ".repository.id"},
+		{Name: "message", JSONPath: "{.head_commit.message}"},
+		{Name: "missing", JSONPath: "{.pull_request.number}"},
+	}
+
+	expected := []v1alpha1.Param{
+		{Name: "branch", Value: "master"},
+		{Name: "sha", Value: "034ab39f12bac07af0188cc9fe7b9f18fba8731f"},
+		{Name: "repo", Value: "pongsatt/githook"},
+		{Name: "repo-id", Value: "12"},
+		{Name: "message", Value: `fix "quote" \ slash`},
+		{Name: "missing", Value: ""},
+	}
+
+	params, err := buildParams(mappings, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(params) != len(expected) {
+		t.Fatalf("expected %d params but got %d", len(expected), len(params))
+	}
+
+	for i := range expected {
+		if params[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected[i], params[i])
+		}
+	}
+}
+
+func TestBuildParamsError(t *testing.T) {
+	testcases := []githookv1alpha1.ParamMapping{
+		{Name: "unknown", Variable: "UNKNOWN"},
+		{Name: "invalid", JSONPath: "{.repository[}"},
+		{Name: "empty"},
+	}
+
+	for _, testcase := range testcases {
+		_, err := buildParams([]githookv1alpha1.ParamMapping{testcase}, PipelineOptions{})
+
+		if err == nil {
+			t.Fatalf("expected error for param %s", testcase.Name)
+		}
+	}
+}
+
+func TestMergeParams(t *testing.T) {
+	existing := []v1alpha1.Param{
+		{Name: "a", Value: "1"},
+		{Name: "b", Value: "2"},
+	}
+	params := []v1alpha1.Param{
+		{Name: "b", Value: "3"},
+		{Name: "c", Value: "4"},
+	}
+
+	merged := mergeParams(existing, params)
+	expected := []v1alpha1.Param{
+		{Name: "a", Value: "1"},
+		{Name: "b", Value: "3"},
+		{Name: "c", Value: "4"},
+	}
+
+	if len(merged) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, merged)
+	}
+
+	for i := range expected {
+		if merged[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, merged)
+		}
+	}
+}
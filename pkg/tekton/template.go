@@ -0,0 +1,198 @@
+package tekton
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	renderModeTemplate = "template"
+)
+
+// TemplateData is the data available to runspec templates
+type TemplateData struct {
+	// Vars are trigger variables keyed by name (ex. BRANCH)
+	Vars map[string]string
+
+	// Payload is the webhook payload as sent by git provider
+	Payload interface{}
+}
+
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+	"truncate": func(length int, s string) string {
+		if len(s) > length {
+			return s[:length]
+		}
+		return s
+	},
+	"default": func(defaultValue string, value interface{}) string {
+		if value == nil || fmt.Sprint(value) == "" {
+			return defaultValue
+		}
+		return fmt.Sprint(value)
+	},
+}
+
+// renderRunSpec renders pipelinerun spec with event data based on render mode
+func renderRunSpec(options PipelineOptions) (string, error) {
+	if options.RenderMode == renderModeTemplate {
+		data := TemplateData{
+			Vars:    Vars(options),
+			Payload: options.Payload,
+		}
+
+		return renderTemplate(options.RunSpecJSON, data)
+	}
+
+	// values of event data are substituted in json strings only so they cannot change the runspec
+	return renderStrings(options.RunSpecJSON, func(text, path string) (string, error) {
+		return replaceVars(text, options), nil
+	})
+}
+
+// renderTemplate renders every string value of json document as a go template
+func renderTemplate(input string, data TemplateData) (string, error) {
+	return renderStrings(input, func(text, path string) (string, error) {
+		return renderString(text, path, data)
+	})
+}
+
+// renderStrings renders every string value of json document with render
+func renderStrings(input string, render func(text, path string) (string, error)) (string, error) {
+	var doc interface{}
+
+	if err := json.Unmarshal([]byte(input), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse runspec: %s", err)
+	}
+
+	rendered, err := renderValue(doc, "runspec", render)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := json.Marshal(rendered)
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
+
+func renderValue(value interface{}, path string, render func(text, path string) (string, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			rendered, err := renderValue(item, path+"."+key, render)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rendered
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			rendered, err := renderValue(item, fmt.Sprintf("%s[%d]", path, i), render)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = rendered
+		}
+		return v, nil
+	case string:
+		return render(v, path)
+	}
+
+	return value, nil
+}
+
+func renderString(text, path string, data TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(path).Option("missingkey=error").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template at %s: %s", path, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template at %s: %s", path, err)
+	}
+
+	return buf.String(), nil
+}
+
+// EvaluateExpression renders go template expression with event data of options.
+// It returns true if the expression renders "true". Keys missing from the payload
+// render no value so the expression does not match events without them.
+func EvaluateExpression(expression string, options PipelineOptions) (bool, error) {
+	data := TemplateData{
+		Vars:    Vars(options),
+		Payload: options.Payload,
+	}
+
+	tmpl, err := template.New("expression").Option("missingkey=default").Funcs(templateFuncs).Parse(expression)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse expression: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return false, fmt.Errorf("failed to evaluate expression: %s", err)
+	}
+
+	return strings.TrimSpace(buf.String()) == "true", nil
+}
+
+// ParseTemplates checks if every string value of runspec in json format is a valid go template
+func ParseTemplates(runSpecJSON string) error {
+	var doc interface{}
+
+	if err := json.Unmarshal([]byte(runSpecJSON), &doc); err != nil {
+		return fmt.Errorf("failed to parse runspec: %s", err)
+	}
+
+	return parseValue(doc, "runspec")
+}
+
+func parseValue(value interface{}, path string) error {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if err := parseValue(item, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := parseValue(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case string:
+		if _, err := template.New(path).Funcs(templateFuncs).Parse(v); err != nil {
+			return fmt.Errorf("failed to parse template at %s: %s", path, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseExpression checks if expression is a valid go template
+func ParseExpression(expression string) error {
+	if _, err := template.New("expression").Funcs(templateFuncs).Parse(expression); err != nil {
+		return fmt.Errorf("failed to parse expression: %s", err)
+	}
+
+	return nil
+}
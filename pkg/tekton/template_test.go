@@ -0,0 +1,139 @@
+package tekton
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := TemplateData{
+		Vars: map[string]string{
+			"BRANCH": "Feature/Login",
+			"TAG":    "",
+		},
+		Payload: map[string]interface{}{
+			"repository": map[string]interface{}{
+				"name": "githook",
+			},
+		},
+	}
+
+	testcases := []struct {
+		input          string
+		expectedOutput string
+	}{
+		{
+			input:          `{"params":[{"name":"image","value":"test.com/{{ .Vars.BRANCH | lower | replace \"/\" \"-\" }}"}]}`,
+			expectedOutput: `{"params":[{"name":"image","value":"test.com/feature-login"}]}`,
+		},
+		{
+			input:          `{"params":[{"name":"tag","value":"{{ .Vars.TAG | default \"latest\" }}"}]}`,
+			expectedOutput: `{"params":[{"name":"tag","value":"latest"}]}`,
+		},
+		{
+			input:          `{"params":[{"name":"repo","value":"{{ .Payload.repository.name }}"}]}`,
+			expectedOutput: `{"params":[{"name":"repo","value":"githook"}]}`,
+		},
+		{
+			input:          `{"params":[{"name":"quoted","value":"{{ if eq .Vars.BRANCH \"master\" }}prod{{ else }}dev\"{{ end }}"}]}`,
+			expectedOutput: `{"params":[{"name":"quoted","value":"dev\""}]}`,
+		},
+		{
+			input:          `{"serviceAccount":"default","timeout":"1h"}`,
+			expectedOutput: `{"serviceAccount":"default","timeout":"1h"}`,
+		},
+	}
+
+	for _, testcase := range testcases {
+		output, err := renderTemplate(testcase.input, data)
+
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if output != testcase.expectedOutput {
+			t.Fatalf("expected : %s but got %s", testcase.expectedOutput, output)
+		}
+	}
+}
+
+func TestRenderTemplateError(t *testing.T) {
+	data := TemplateData{
+		Vars:    map[string]string{},
+		Payload: map[string]interface{}{},
+	}
+
+	testcases := []struct {
+		input         string
+		expectedError string
+	}{
+		{
+			input:         `{"params":[{"name":"a","value":"{{ .Vars.BRANCH "}]}`,
+			expectedError: "failed to parse template at runspec.params[0].value",
+		},
+		{
+			input:         `{"params":[{"name":"a","value":"{{ .Payload.missing }}"}]}`,
+			expectedError: "failed to render template at runspec.params[0].value",
+		},
+		{
+			input:         `{"params":`,
+			expectedError: "failed to parse runspec",
+		},
+	}
+
+	for _, testcase := range testcases {
+		_, err := renderTemplate(testcase.input, data)
+
+		if err == nil || !strings.Contains(err.Error(), testcase.expectedError) {
+			t.Fatalf("expected error %q but got %v", testcase.expectedError, err)
+		}
+	}
+}
+
+func TestEvaluateExpression(t *testing.T) {
+	options := PipelineOptions{
+		GitBranch: "main",
+		Payload:   map[string]interface{}{"action": "opened"},
+	}
+
+	testcases := []struct {
+		expression    string
+		expected      bool
+		expectedError string
+	}{
+		{expression: `{{ eq .Vars.BRANCH "main" }}`, expected: true},
+		{expression: `{{ eq .Payload.action "closed" }}`, expected: false},
+		{expression: ` {{ if eq .Vars.BRANCH "main" }}true{{ end }} `, expected: true},
+		{expression: `{{ eq .Payload.ref_type "tag" }}`, expected: false},
+		{expression: `{{ .Payload.missing.field }}`, expected: false},
+		{expression: `{{ .Vars.BRANCH.field }}`, expectedError: "failed to evaluate expression"},
+		{expression: `{{ eq .Vars.BRANCH `, expectedError: "failed to parse expression"},
+	}
+
+	for _, testcase := range testcases {
+		matched, err := EvaluateExpression(testcase.expression, options)
+
+		if testcase.expectedError != "" {
+			if err == nil || !strings.Contains(err.Error(), testcase.expectedError) {
+				t.Fatalf("expected error %q but got %v", testcase.expectedError, err)
+			}
+			continue
+		}
+
+		if err != nil || matched != testcase.expected {
+			t.Fatalf("expected %s to be %v but got %v, %v", testcase.expression, testcase.expected, matched, err)
+		}
+	}
+}
+
+func TestParseTemplates(t *testing.T) {
+	if err := ParseTemplates(`{"params":[{"name":"a","value":"{{ .Vars.BRANCH | lower }}"}]}`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err := ParseTemplates(`{"params":[{"name":"a","value":"{{ .Vars.BRANCH "}]}`)
+
+	if err == nil || !strings.Contains(err.Error(), "failed to parse template at runspec.params[0].value") {
+		t.Fatalf("expected template error but got %v", err)
+	}
+}
@@ -1,18 +1,90 @@
 package tekton
 
 import (
-	"fmt"
-	"strings"
+	"regexp"
+	"strconv"
 )
 
-func replaceVars(input string, opts PipelineOptions) string {
-	return replaceVar(input, "COMMIT", shorten(opts.GitCommit))
+// Variables available for substitution in pipelinerun spec
+const (
+	// VarCommit short commit sha (kept for backward compatibility)
+	VarCommit = "COMMIT"
+	// VarCommitSHA full commit sha
+	VarCommitSHA = "COMMIT_SHA"
+	// VarCommitShortSHA short commit sha
+	VarCommitShortSHA = "COMMIT_SHORT_SHA"
+	// VarRevision git revision as given by the event (ex. refs/heads/master)
+	VarRevision = "REVISION"
+	// VarBranch branch name without refs/heads/
+	VarBranch = "BRANCH"
+	// VarTag tag name without refs/tags/
+	VarTag = "TAG"
+	// VarRepoURL url of the repository
+	VarRepoURL = "REPO_URL"
+	// VarRepoOwner owner (user, organization or group) of the repository
+	VarRepoOwner = "REPO_OWNER"
+	// VarRepoName name of the repository
+	VarRepoName = "REPO_NAME"
+	// VarEventType event type as given by the event header (ex. push)
+	VarEventType = "EVENT_TYPE"
+	// VarAuthor author of the head commit, pull request, issue or comment
+	VarAuthor = "AUTHOR"
+	// VarCommitter committer of the head commit or user who triggered the event
+	VarCommitter = "COMMITTER"
+	// VarPRNumber pull request or merge request number
+	VarPRNumber = "PR_NUMBER"
+	// VarPRSourceBranch source branch of pull request or merge request
+	VarPRSourceBranch = "PR_SOURCE_BRANCH"
+	// VarPRTargetBranch target branch of pull request or merge request
+	VarPRTargetBranch = "PR_TARGET_BRANCH"
+	// VarDeliveryID unique id of the webhook delivery
+	VarDeliveryID = "DELIVERY_ID"
+)
+
+var varPattern = regexp.MustCompile(`\$[A-Z_]+`)
+
+// Vars returns values of all variables from pipeline options
+func Vars(opts PipelineOptions) map[string]string {
+	prNumber := ""
+	if opts.PullRequestNumber > 0 {
+		prNumber = strconv.Itoa(opts.PullRequestNumber)
+	}
+
+	return map[string]string{
+		VarCommit:         shorten(opts.GitCommit),
+		VarCommitSHA:      opts.GitCommit,
+		VarCommitShortSHA: shorten(opts.GitCommit),
+		VarRevision:       opts.GitRevision,
+		VarBranch:         opts.GitBranch,
+		VarTag:            opts.GitTag,
+		VarRepoURL:        opts.GitURL,
+		VarRepoOwner:      opts.RepoOwner,
+		VarRepoName:       opts.RepoName,
+		VarEventType:      opts.EventType,
+		VarAuthor:         opts.Author,
+		VarCommitter:      opts.Committer,
+		VarPRNumber:       prNumber,
+		VarPRSourceBranch: opts.SourceBranch,
+		VarPRTargetBranch: opts.TargetBranch,
+		VarDeliveryID:     opts.DeliveryID,
+	}
 }
 
-func replaceVar(input, varName, value string) string {
-	look := fmt.Sprintf("$%s", varName)
+// replaceVars replaces $VARIABLE in input with its value.
+// The longest variable name is matched so $COMMIT_SHA is not taken as $COMMIT
+// while $BRANCH_NAME, not a variable, is taken as $BRANCH followed by _NAME.
+func replaceVars(input string, opts PipelineOptions) string {
+	vars := Vars(opts)
+
+	return varPattern.ReplaceAllStringFunc(input, func(match string) string {
+		for end := len(match); end > 1; end-- {
+			if value, ok := vars[match[1:end]]; ok {
+				return value + match[end:]
+			}
+		}
 
-	return strings.ReplaceAll(input, look, value)
+		return match
+	})
 }
 
 func shorten(hash string) string {
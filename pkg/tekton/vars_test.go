@@ -1,6 +1,7 @@
 package tekton
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -50,3 +51,113 @@ func TestReplaceCommitVar(t *testing.T) {
 	}
 
 }
+
+func TestReplaceVars(t *testing.T) {
+	opts := PipelineOptions{
+		GitURL:            "https://github.com/pongsatt/githook",
+		GitRevision:       "refs/heads/feature/login",
+		GitCommit:         "034ab39f12bac07af0188cc9fe7b9f18fba8731f",
+		GitBranch:         "feature/login",
+		RepoOwner:         "pongsatt",
+		RepoName:          "githook",
+		EventType:         "pull_request",
+		DeliveryID:        "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		Author:            "alice",
+		Committer:         "bob",
+		PullRequestNumber: 12,
+		SourceBranch:      "feature/login",
+		TargetBranch:      "master",
+	}
+
+	testcases := []struct {
+		input          string
+		expectedOutput string
+	}{
+		{
+			input:          "$COMMIT_SHA",
+			expectedOutput: "034ab39f12bac07af0188cc9fe7b9f18fba8731f",
+		},
+		{
+			input:          "$COMMIT_SHORT_SHA-$COMMIT",
+			expectedOutput: "034ab39f12-034ab39f12",
+		},
+		{
+			input:          "$COMMITX",
+			expectedOutput: "034ab39f12X",
+		},
+		{
+			input:          "$BRANCH/$TAG",
+			expectedOutput: "feature/login/",
+		},
+		{
+			input:          "$REVISION",
+			expectedOutput: "refs/heads/feature/login",
+		},
+		{
+			input:          "$REPO_URL $REPO_OWNER $REPO_NAME",
+			expectedOutput: "https://github.com/pongsatt/githook pongsatt githook",
+		},
+		{
+			input:          "$EVENT_TYPE by $AUTHOR and $COMMITTER",
+			expectedOutput: "pull_request by alice and bob",
+		},
+		{
+			input:          "#$PR_NUMBER $PR_SOURCE_BRANCH -> $PR_TARGET_BRANCH",
+			expectedOutput: "#12 feature/login -> master",
+		},
+		{
+			input:          "$DELIVERY_ID",
+			expectedOutput: "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		},
+		{
+			input:          "$BRANCH_NAME",
+			expectedOutput: "feature/login_NAME",
+		},
+		{
+			input:          "$UNKNOWN $",
+			expectedOutput: "$UNKNOWN $",
+		},
+	}
+
+	for _, testcase := range testcases {
+		output := replaceVars(testcase.input, opts)
+
+		if output != testcase.expectedOutput {
+			t.Fatalf("expected : %s but got %s", testcase.expectedOutput, output)
+		}
+	}
+}
+
+func TestRenderRunSpecVars(t *testing.T) {
+	opts := PipelineOptions{
+		RunSpecJSON: `{"serviceAccount":"runner","params":[{"name":"branch","value":"$BRANCH"},{"name":"author","value":"$AUTHOR"}]}`,
+		GitBranch:   `x","serviceAccount":"admin`,
+		Author:      `Alice "Al" O'Neil`,
+	}
+
+	output, err := renderRunSpec(opts)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var runSpec struct {
+		ServiceAccount string `json:"serviceAccount"`
+		Params         []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"params"`
+	}
+
+	if err := json.Unmarshal([]byte(output), &runSpec); err != nil {
+		t.Fatalf("expected json runspec but got %s: %s", output, err)
+	}
+
+	if runSpec.ServiceAccount != "runner" {
+		t.Fatalf("expected service account runner but got %s", runSpec.ServiceAccount)
+	}
+
+	if runSpec.Params[0].Value != opts.GitBranch || runSpec.Params[1].Value != opts.Author {
+		t.Fatalf("expected branch and author values as is but got %s", output)
+	}
+}
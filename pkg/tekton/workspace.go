@@ -0,0 +1,203 @@
+package tekton
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AnnotationWorkspaceClaims is the annotation of pipelinerun holding comma separated names of
+// the persistent volume claims created for its workspaces
+const AnnotationWorkspaceClaims = provenancePrefix + "workspace-claims"
+
+// workspaceBinding returns the workspace binding of pipelinerun spec for workspace template.
+// claimName is the name of the claim created for PersistentVolumeClaim template.
+func workspaceBinding(template githookv1alpha1.WorkspaceTemplate, claimName string) (map[string]interface{}, error) {
+	binding := map[string]interface{}{
+		"name": template.Name,
+	}
+
+	if template.SubPath != "" {
+		binding["subPath"] = template.SubPath
+	}
+
+	switch {
+	case template.EmptyDir != nil:
+		emptyDir, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template.EmptyDir)
+		if err != nil {
+			return nil, err
+		}
+		binding["emptyDir"] = emptyDir
+	case template.VolumeClaimTemplate != nil:
+		claim, err := runtime.DefaultUnstructuredConverter.ToUnstructured(template.VolumeClaimTemplate)
+		if err != nil {
+			return nil, err
+		}
+		delete(claim, "status")
+		binding["volumeClaimTemplate"] = claim
+	case template.PersistentVolumeClaim != nil:
+		binding["persistentVolumeClaim"] = map[string]interface{}{
+			"claimName": claimName,
+		}
+	default:
+		return nil, fmt.Errorf("workspace %s: one of emptyDir, volumeClaimTemplate or persistentVolumeClaim is required", template.Name)
+	}
+
+	return binding, nil
+}
+
+// bindWorkspaces adds bindings to workspaces of unstructured pipelinerun spec
+// replacing workspaces with the same name
+func bindWorkspaces(pipelineRun *unstructured.Unstructured, bindings []map[string]interface{}) error {
+	existing, _, err := unstructured.NestedSlice(pipelineRun.Object, "spec", "workspaces")
+
+	if err != nil {
+		return fmt.Errorf("invalid workspaces of pipelinerun spec: %s", err)
+	}
+
+	replaced := map[string]bool{}
+	for _, binding := range bindings {
+		replaced[fmt.Sprint(binding["name"])] = true
+	}
+
+	workspaces := make([]interface{}, 0, len(existing)+len(bindings))
+	for _, workspace := range existing {
+		if fields, ok := workspace.(map[string]interface{}); ok && replaced[fmt.Sprint(fields["name"])] {
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	for _, binding := range bindings {
+		workspaces = append(workspaces, binding)
+	}
+
+	return unstructured.SetNestedSlice(pipelineRun.Object, workspaces, "spec", "workspaces")
+}
+
+// createWorkspaces creates claims of PersistentVolumeClaim templates and binds workspace
+// templates to pipelinerun annotated with the claims. It returns the claims created.
+func (client *Client) createWorkspaces(options PipelineOptions, pipelineRun *unstructured.Unstructured) ([]*corev1.PersistentVolumeClaim, error) {
+	claims := []*corev1.PersistentVolumeClaim{}
+	bindings := []map[string]interface{}{}
+
+	for _, template := range options.Workspaces {
+		claimName := ""
+
+		if template.PersistentVolumeClaim != nil && template.EmptyDir == nil && template.VolumeClaimTemplate == nil {
+			claim := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					GenerateName: fmt.Sprintf("%s-%s-", options.Prefix, template.Name),
+					Namespace:    options.Namespace,
+					Labels:       pipelineRunLabels(options),
+				},
+				Spec: *template.PersistentVolumeClaim,
+			}
+
+			claim, err := client.kube.CoreV1().PersistentVolumeClaims(options.Namespace).Create(claim)
+
+			if err != nil {
+				client.deleteClaims(claims)
+				return nil, fmt.Errorf("failed to create persistent volume claim of workspace %s: %s", template.Name, err)
+			}
+
+			claims = append(claims, claim)
+			claimName = claim.Name
+		}
+
+		binding, err := workspaceBinding(template, claimName)
+
+		if err != nil {
+			client.deleteClaims(claims)
+			return nil, err
+		}
+
+		bindings = append(bindings, binding)
+	}
+
+	if len(bindings) == 0 {
+		return claims, nil
+	}
+
+	if err := bindWorkspaces(pipelineRun, bindings); err != nil {
+		client.deleteClaims(claims)
+		return nil, err
+	}
+
+	if len(claims) > 0 {
+		names := []string{}
+		for _, claim := range claims {
+			names = append(names, claim.Name)
+		}
+
+		annotations := pipelineRun.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[AnnotationWorkspaceClaims] = strings.Join(names, ",")
+		pipelineRun.SetAnnotations(annotations)
+	}
+
+	return claims, nil
+}
+
+// workspaceClaims returns the claims pipelinerun is annotated with
+func workspaceClaims(pipelineRun metav1.Object) []*corev1.PersistentVolumeClaim {
+	claims := []*corev1.PersistentVolumeClaim{}
+
+	for _, name := range strings.Split(pipelineRun.GetAnnotations()[AnnotationWorkspaceClaims], ",") {
+		if name != "" {
+			claims = append(claims, &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pipelineRun.GetNamespace()},
+			})
+		}
+	}
+
+	return claims
+}
+
+// ownClaims sets owner of claims pipelinerun is annotated with so they are deleted with the owner
+func (client *Client) ownClaims(pipelineRun metav1.Object, owner metav1.OwnerReference) error {
+	claims := workspaceClaims(pipelineRun)
+
+	if len(claims) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"ownerReferences": []metav1.OwnerReference{owner},
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, claim := range claims {
+		_, err := client.kube.CoreV1().PersistentVolumeClaims(claim.Namespace).Patch(claim.Name, types.MergePatchType, patch)
+
+		if err != nil {
+			return fmt.Errorf("failed to set owner of persistent volume claim %s: %s", claim.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteClaims deletes claims of pipelinerun failed to be created
+func (client *Client) deleteClaims(claims []*corev1.PersistentVolumeClaim) {
+	for _, claim := range claims {
+		if err := client.kube.CoreV1().PersistentVolumeClaims(claim.Namespace).Delete(claim.Name, &metav1.DeleteOptions{}); err != nil {
+			log.Printf("failed to delete persistent volume claim %s: %s", claim.Name, err)
+		}
+	}
+}
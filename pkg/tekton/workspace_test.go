@@ -0,0 +1,130 @@
+package tekton
+
+import (
+	"testing"
+
+	githookv1alpha1 "gitlab.com/pongsatt/githook/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCreateWorkspaces(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	// the fake does not generate names
+	kubeClient.PrependReactor("create", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		claim := action.(k8stesting.CreateAction).GetObject().(*corev1.PersistentVolumeClaim)
+		claim.Name = claim.GenerateName + "x7k2p"
+		return false, nil, nil
+	})
+	client := &Client{kube: kubeClient}
+
+	options := PipelineOptions{
+		Namespace: "ci",
+		Prefix:    "sample",
+		GitBranch: "master",
+		Workspaces: []githookv1alpha1.WorkspaceTemplate{
+			{Name: "source", PersistentVolumeClaim: &corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			}},
+			{Name: "cache", SubPath: "go", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			{Name: "output", VolumeClaimTemplate: &corev1.PersistentVolumeClaim{}},
+		},
+	}
+
+	pipelineRun := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "PipelineRun",
+		"spec": map[string]interface{}{
+			"workspaces": []interface{}{
+				map[string]interface{}{"name": "source", "emptyDir": map[string]interface{}{}},
+				map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "settings"}},
+			},
+		},
+	}}
+
+	claims, err := client.createWorkspaces(options, pipelineRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(claims) != 1 || claims[0].Name != "sample-source-x7k2p" || claims[0].Labels[LabelBranch] != "master" {
+		t.Fatalf("expected a labelled claim of source workspace but got %v", claims)
+	}
+
+	workspaces, _, _ := unstructured.NestedSlice(pipelineRun.Object, "spec", "workspaces")
+	bindings := map[string]map[string]interface{}{}
+	for _, workspace := range workspaces {
+		fields := workspace.(map[string]interface{})
+		bindings[fields["name"].(string)] = fields
+	}
+
+	if len(bindings) != 4 || bindings["config"]["configMap"] == nil {
+		t.Fatalf("expected workspaces of spec to be kept but got %v", workspaces)
+	}
+
+	if claimName, _, _ := unstructured.NestedString(bindings["source"], "persistentVolumeClaim", "claimName"); claimName != "sample-source-x7k2p" {
+		t.Fatalf("expected source workspace to be replaced with the claim but got %v", bindings["source"])
+	}
+
+	if bindings["cache"]["subPath"] != "go" || bindings["cache"]["emptyDir"] == nil || bindings["output"]["volumeClaimTemplate"] == nil {
+		t.Fatalf("unexpected bindings %v", bindings)
+	}
+
+	pipelineRun.SetNamespace("ci")
+	pipelineRun.SetName("sample-created")
+	pipelineRun.SetUID("0ac2d2ab-1d47-4b0c-9d8e-3c5e4a3a1f6b")
+
+	if pipelineRun.GetAnnotations()[AnnotationWorkspaceClaims] != "sample-source-x7k2p" {
+		t.Fatalf("expected pipelinerun to be annotated with the claim but got %v", pipelineRun.GetAnnotations())
+	}
+
+	if err := client.ownClaims(pipelineRun, pipelineRunOwner(pipelineRun)); err != nil {
+		t.Fatal(err)
+	}
+
+	claim, _ := kubeClient.CoreV1().PersistentVolumeClaims("ci").Get("sample-source-x7k2p", metav1.GetOptions{})
+
+	if len(claim.OwnerReferences) != 1 || claim.OwnerReferences[0].APIVersion != "tekton.dev/v1" || claim.OwnerReferences[0].UID != pipelineRun.GetUID() {
+		t.Fatalf("expected claim to be owned by pipelinerun but got %v", claim.OwnerReferences)
+	}
+}
+
+func TestWorkspaceBindingRequiresVolume(t *testing.T) {
+	if _, err := workspaceBinding(githookv1alpha1.WorkspaceTemplate{Name: "source"}, ""); err == nil {
+		t.Fatal("expected workspace without volume to be rejected")
+	}
+}
+
+func TestOwnResources(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset(
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "sample-source-x7k2p"}},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "ci", Name: "sample-cache-m4q9z"}},
+	)
+	client := &Client{kube: kubeClient}
+
+	pipelineRun := &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "tekton.dev/v1beta1", "kind": "PipelineRun"}}
+	pipelineRun.SetNamespace("ci")
+	pipelineRun.SetName("sample-created")
+	pipelineRun.SetUID("0ac2d2ab-1d47-4b0c-9d8e-3c5e4a3a1f6b")
+	pipelineRun.SetAnnotations(map[string]string{AnnotationWorkspaceClaims: "sample-source-x7k2p,sample-cache-m4q9z"})
+
+	if err := client.OwnResources(pipelineRun); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"sample-source-x7k2p", "sample-cache-m4q9z"} {
+		claim, _ := kubeClient.CoreV1().PersistentVolumeClaims("ci").Get(name, metav1.GetOptions{})
+
+		if len(claim.OwnerReferences) != 1 || claim.OwnerReferences[0].APIVersion != "tekton.dev/v1beta1" || claim.OwnerReferences[0].Name != "sample-created" {
+			t.Fatalf("expected claim %s to be owned by pipelinerun but got %v", name, claim.OwnerReferences)
+		}
+	}
+}